@@ -8,8 +8,23 @@ const (
 	// Organization IDs environment variable (comma-separated list)
 	EnvOrganizationIDs = "ORGANIZATION_IDS"
 
+	// Organization discovery environment variable; when "true" ORGANIZATION_IDS only filters the discovered organizations
+	EnvOrganizationDiscovery = "ORGANIZATION_DISCOVERY"
+
 	// License key environment variable
 	EnvLicenseKey = "LICENSE_KEY"
+
+	// License server URL environment variable (overrides the profile)
+	EnvLicenseURL = "LICENSE_URL"
+
+	// License server profile environment variable (prod, dev, staging or custom)
+	EnvLicenseProfile = "LICENSE_PROFILE"
+
+	// Offline signed license file path environment variable (replaces the license server when set)
+	EnvLicenseFile = "LICENSE_FILE"
+
+	// License snapshot file path environment variable (persists validation results across restarts)
+	EnvLicenseSnapshotFile = "LICENSE_SNAPSHOT_FILE"
 )
 
 // Special organization ID values
@@ -7,13 +7,21 @@ import "errors"
 var (
 	ErrInternalServer = errors.New("LCS-0001") // Internal server error
 
-	// Global license validation errors (0002-0003)
-	ErrNoOrganizationIDs = errors.New("LCS-0002") // No organization IDs configured
-	ErrNoValidLicenses   = errors.New("LCS-0003") // No valid licenses found for any organization
+	// Global license validation errors (0002-0004)
+	ErrNoOrganizationIDs  = errors.New("LCS-0002") // No organization IDs configured
+	ErrNoValidLicenses    = errors.New("LCS-0003") // No valid licenses found for any organization
+	ErrInvalidLicenseFile = errors.New("LCS-0004") // Offline license file is malformed or its signature cannot be verified
 
-	// Request-specific license validation errors (0010-0012)
+	// Request-specific license validation errors (0010-0016)
 	ErrMissingOrgIDHeader       = errors.New("LCS-0010") // Organization ID header is missing
 	ErrUnknownOrgIDHeader       = errors.New("LCS-0011") // Organization ID header is unknown
 	ErrOrgLicenseValidationFail = errors.New("LCS-0012") // Failed to validate organization license
 	ErrOrgLicenseInvalid        = errors.New("LCS-0013") // Organization license is invalid
+	ErrConflictingOrgID         = errors.New("LCS-0014") // Request sources carry different organization IDs
+	ErrOrgClaimMismatch         = errors.New("LCS-0015") // Token organization claim differs from the organization ID header
+	ErrInvalidOrgToken          = errors.New("LCS-0016") // Bearer token is missing, invalid or has no organization claim
+
+	// Admin handler errors (0020-0021)
+	ErrAdminForbidden      = errors.New("LCS-0020") // Admin request is not authorized
+	ErrInvalidAdminRequest = errors.New("LCS-0021") // Admin action is unknown or misses its organization ID
 )
@@ -4,6 +4,20 @@ package constant
 const (
 	// OrganizationIDHeader defines the header name for organization ID in requests
 	OrganizationIDHeader = "X-Organization-ID"
+	// DefaultLicenseHeaderPrefix is the default prefix of the license status response headers
+	DefaultLicenseHeaderPrefix = "X-License-"
+	// LicenseStatusHeader names the license status header, after the prefix
+	LicenseStatusHeader = "Status"
+	// LicenseExpiresInDaysHeader names the days left before the license (or grace period) ends, after the prefix
+	LicenseExpiresInDaysHeader = "Expires-In-Days"
+	// LicenseGracePeriodHeader names the grace period header, after the prefix
+	LicenseGracePeriodHeader = "Grace-Period"
+	// LicenseTrialHeader names the trial license header, after the prefix
+	LicenseTrialHeader = "Trial"
+	// AdminActionParam names the query parameter selecting the action of a POST to the admin handler
+	AdminActionParam = "action"
+	// AdminOrganizationIDParam names the query parameter carrying the organization ID of an admin action
+	AdminOrganizationIDParam = "organizationId"
 )
 
 // TimeConstants defines timeout and interval values
@@ -12,4 +26,14 @@ const (
 	DefaultHTTPTimeoutSeconds = 5
 	// DefaultRefreshIntervalDays is the default license refresh interval in days
 	DefaultRefreshIntervalDays = 7
+	// DefaultMaxRetries is the default number of background validation attempts
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoffSeconds is the default initial backoff between background validation attempts in seconds
+	DefaultRetryBackoffSeconds = 5
+	// DefaultMaxConcurrency is the default number of organizations validated in parallel
+	DefaultMaxConcurrency = 8
+	// DefaultStartupRetryMaxBackoffSeconds caps the backoff between the retries of a failed startup validation
+	DefaultStartupRetryMaxBackoffSeconds = 300
+	// DefaultSnapshotMaxAgeDays is how long a persisted validation result can be used while the license server is unreachable
+	DefaultSnapshotMaxAgeDays = 14
 )
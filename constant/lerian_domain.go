@@ -6,4 +6,18 @@ const (
 	ProdLicenseGatewayBaseURL = "https://license.lerian.io"
 	// DevLicenseGatewayBaseURL is the development license service URL
 	DevLicenseGatewayBaseURL = "https://license.dev.lerian.io"
+	// StagingLicenseGatewayBaseURL is the staging license service URL
+	StagingLicenseGatewayBaseURL = "https://license.staging.lerian.io"
+)
+
+// ProfileConstants defines the named license server profiles
+const (
+	// ProfileProd selects the production license service
+	ProfileProd = "prod"
+	// ProfileDev selects the development license service
+	ProfileDev = "dev"
+	// ProfileStaging selects the staging license service
+	ProfileStaging = "staging"
+	// ProfileCustom selects the license service given by LICENSE_URL or an explicit URL
+	ProfileCustom = "custom"
 )
@@ -7,13 +7,20 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/LerianStudio/lib-commons/commons/log"
 	cn "github.com/LerianStudio/lib-license-go/constant"
 	"github.com/LerianStudio/lib-license-go/internal/config"
+	"github.com/LerianStudio/lib-license-go/internal/telemetry"
 	"github.com/LerianStudio/lib-license-go/model"
 	"github.com/LerianStudio/lib-license-go/pkg"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client handles communication with the license API
@@ -21,6 +28,8 @@ type Client struct {
 	httpClient *http.Client
 	config     *config.ClientConfig
 	logger     log.Logger
+	metrics    *telemetry.Metrics
+	tracer     trace.Tracer
 	// IsGlobal indicates if this client is operating in global plugin mode
 	IsGlobal bool
 }
@@ -40,6 +49,7 @@ func New(cfg *config.ClientConfig, httpClient *http.Client, logger log.Logger) *
 		httpClient: httpClient,
 		config:     cfg,
 		logger:     logger,
+		tracer:     telemetry.NewTracer(nil),
 		IsGlobal:   isGlobal,
 	}
 }
@@ -51,30 +61,26 @@ func (c *Client) SetHTTPClient(client *http.Client) {
 	}
 }
 
-// GetHTTPClient returns the current HTTP client
-func (c *Client) GetHTTPClient() *http.Client {
-	return c.httpClient
+// SetMetrics records the latency of the license gateway requests
+func (c *Client) SetMetrics(metrics *telemetry.Metrics) {
+	c.metrics = metrics
 }
 
-// baseURL is used to store the license validation API URL
-// It's initialized from LICENSE_URL environment variable if available,
-// otherwise defaults to the predefined value
-var baseURL = getBaseURLFromEnvOrDefault()
-
-// getBaseURLFromEnvOrDefault returns the license URL based on IS_DEVELOPMENT
-// If true, returns the dev URL, otherwise returns the production URL
-func getBaseURLFromEnvOrDefault() string {
-	isDev := os.Getenv(cn.EnvIsDevelopment)
-	if isDev == "true" {
-		return cn.DevLicenseGatewayBaseURL
+// SetTracer records a span for each license gateway request and propagates its trace context
+func (c *Client) SetTracer(tracer trace.Tracer) {
+	if tracer != nil {
+		c.tracer = tracer
 	}
+}
 
-	return cn.ProdLicenseGatewayBaseURL
+// GetHTTPClient returns the current HTTP client
+func (c *Client) GetHTTPClient() *http.Client {
+	return c.httpClient
 }
 
-// ValidateOrganization validates the license with the provided organization ID
+// Validate validates the license with the provided organization ID
 // Returns the first successful validation result or the last error encountered
-func (c *Client) ValidateOrganization(ctx context.Context, orgID string) (model.ValidationResult, error) {
+func (c *Client) Validate(ctx context.Context, orgID string) (model.ValidationResult, error) {
 	result, err := c.validateForOrganization(ctx, orgID)
 	if err != nil {
 		return model.ValidationResult{}, err
@@ -85,8 +91,6 @@ func (c *Client) ValidateOrganization(ctx context.Context, orgID string) (model.
 
 // validateForOrganization performs the license validation API call for a specific organization ID
 func (c *Client) validateForOrganization(ctx context.Context, orgID string) (model.ValidationResult, error) {
-	url := fmt.Sprintf("%s/licenses/validate", baseURL)
-
 	// Request body with application name, organization ID, and license key
 	reqBody := map[string]string{
 		"resourceName":   c.config.AppName,
@@ -94,14 +98,41 @@ func (c *Client) validateForOrganization(ctx context.Context, orgID string) (mod
 		"organizationId": orgID,
 	}
 
+	var result model.ValidationResult
+	if err := c.post(ctx, "/licenses/validate", reqBody, &result); err != nil {
+		return model.ValidationResult{}, err
+	}
+
+	return result, nil
+}
+
+// Organizations returns the organization IDs the license key entitles for the application
+func (c *Client) Organizations(ctx context.Context) ([]string, error) {
+	reqBody := map[string]string{
+		"resourceName": c.config.AppName,
+		"licenseKey":   c.config.LicenseKey,
+	}
+
+	var result model.OrganizationsResponse
+	if err := c.post(ctx, "/licenses/organizations", reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return result.OrganizationIDs, nil
+}
+
+// post sends a JSON request to the license API and decodes a successful response into out
+func (c *Client) post(ctx context.Context, path string, reqBody map[string]string, out any) error {
+	url := fmt.Sprintf("%s%s", c.config.BaseURL, path)
+
 	body, err := json.Marshal(reqBody)
 	if err != nil {
-		return model.ValidationResult{}, fmt.Errorf("failed to marshal request body: %w", err)
+		return fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
-		return model.ValidationResult{}, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 	// Set required headers
 	req.Header.Set("Content-Type", "application/json")
@@ -109,24 +140,67 @@ func (c *Client) validateForOrganization(ctx context.Context, orgID string) (mod
 	// Add organization ID as API key in header
 	req.Header.Set("x-api-key", c.config.LicenseKey)
 
+	operation := strings.TrimPrefix(path, "/licenses/")
+
+	// The span records the request line only; the license key travels in the body and the x-api-key header
+	ctx, span := c.startRequestSpan(ctx, req, operation)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.metrics.RecordGatewayRequest(ctx, operation, time.Since(start), 0, err)
+		span.SetAttributes(semconv.ErrorTypeKey.String(telemetry.ErrorType(err)))
+		span.SetStatus(codes.Error, err.Error())
 		c.logger.Warnf("License validation request failed - error: %s", err.Error())
-		return model.ValidationResult{}, fmt.Errorf("request failed: %w", err)
+
+		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	c.metrics.RecordGatewayRequest(ctx, operation, time.Since(start), resp.StatusCode, nil)
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		err := c.handleErrorResponse(resp)
-		return model.ValidationResult{}, err
+		return c.handleErrorResponse(resp)
 	}
 
-	var result model.ValidationResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return model.ValidationResult{}, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return result, nil
+	return nil
+}
+
+// startRequestSpan starts the client span of a license gateway request with the HTTP semantic conventions
+func (c *Client) startRequestSpan(ctx context.Context, req *http.Request, operation string) (context.Context, trace.Span) {
+	port, _ := strconv.Atoi(req.URL.Port())
+	if port == 0 {
+		port = 443
+		if req.URL.Scheme == "http" {
+			port = 80
+		}
+	}
+
+	return c.tracer.Start(ctx, req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodPost,
+			semconv.URLFull(req.URL.Redacted()),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.ServerPort(port),
+			telemetry.OperationKey.String(operation),
+		),
+	)
 }
 
 // handleErrorResponse processes non-200 HTTP responses.
@@ -1,20 +1,25 @@
 package cache
 
 import (
+	"time"
+
 	"github.com/LerianStudio/lib-commons/commons/log"
 	"github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/telemetry"
 	"github.com/LerianStudio/lib-license-go/model"
 	"github.com/dgraph-io/ristretto/v2"
 )
 
 // Manager handles caching of license validation results
 type Manager struct {
-	cache  *ristretto.Cache[string, model.ValidationResult]
-	logger log.Logger
+	cache   *ristretto.Cache[string, model.ValidationResult]
+	ttl     time.Duration
+	logger  log.Logger
+	metrics *telemetry.Metrics
 }
 
-// New creates a new cache manager
-func New(logger log.Logger) (*Manager, error) {
+// New creates a new cache manager storing results for the given TTL
+func New(ttl time.Duration, logger log.Logger) (*Manager, error) {
 	cache, err := ristretto.NewCache[string, model.ValidationResult](&ristretto.Config[string, model.ValidationResult]{
 		NumCounters:            constant.CacheNumCounters,
 		MaxCost:                constant.CacheMaxCost,
@@ -27,13 +32,22 @@ func New(logger log.Logger) (*Manager, error) {
 
 	return &Manager{
 		cache:  cache,
+		ttl:    ttl,
 		logger: logger,
 	}, nil
 }
 
+// SetMetrics records the cache hits and misses
+func (m *Manager) SetMetrics(metrics *telemetry.Metrics) {
+	m.metrics = metrics
+}
+
 // Get retrieves a cached validation result by organization ID
 func (m *Manager) Get(orgID string) (model.ValidationResult, bool) {
-	if val, found := m.cache.Get(orgID); found {
+	val, found := m.cache.Get(orgID)
+	m.metrics.RecordCacheLookup(found)
+
+	if found {
 		m.logger.Debugf("License cached for org %s [expires: %d days | grace: %t]",
 			orgID, val.ExpiryDaysLeft, val.ActiveGracePeriod)
 		return val, true
@@ -42,10 +56,10 @@ func (m *Manager) Get(orgID string) (model.ValidationResult, bool) {
 	return model.ValidationResult{}, false
 }
 
-// Store caches a validation result with a fixed TTL
+// Store caches a validation result with the configured TTL
 func (m *Manager) Store(orgID string, result model.ValidationResult) {
-	// Store with a fixed TTL for security (ensure regular re-validation)
-	m.cache.SetWithTTL(orgID, result, 1, constant.CacheTTL)
+	// Store with a bounded TTL for security (ensure regular re-validation)
+	m.cache.SetWithTTL(orgID, result, 1, m.ttl)
 
 	// Wait for any pending writes to complete
 	m.cache.Wait()
@@ -53,3 +67,11 @@ func (m *Manager) Store(orgID string, result model.ValidationResult) {
 	// Log the cached result with a simpler format for test compatibility
 	m.logger.Debugf("Stored license validation for org %s", orgID)
 }
+
+// Delete evicts the cached validation result of an organization
+func (m *Manager) Delete(orgID string) {
+	m.cache.Del(orgID)
+	m.cache.Wait()
+
+	m.logger.Debugf("Evicted license validation for org %s", orgID)
+}
@@ -1,10 +1,24 @@
 package config
 
 import (
+	"context"
+	"crypto/ed25519"
 	"errors"
+	"fmt"
+	"net/http"
 	"time"
+
+	"github.com/LerianStudio/lib-license-go/model"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Provider validates the license of a single organization.
+// It has the method set of provider.LicenseProvider, which cannot be imported here without a cycle.
+type Provider interface {
+	Validate(ctx context.Context, orgID string) (model.ValidationResult, error)
+}
+
 // ClientConfig contains the configuration for the license client
 type ClientConfig struct {
 	AppName         string
@@ -12,6 +26,44 @@ type ClientConfig struct {
 	OrganizationIDs []string // List of valid organization IDs
 	HTTPTimeout     time.Duration
 	RefreshInterval time.Duration
+	// Profile names the license server (prod, dev, staging or custom) and BaseURL is its endpoint.
+	// Both are resolved by ResolveBaseURL when not set explicitly.
+	Profile string
+	BaseURL string
+	// HTTPClient overrides the client used to reach the license server; built from HTTPTimeout when nil
+	HTTPClient *http.Client
+	// MaxRetries and RetryBackoff control background validation retries; the backoff doubles on each attempt
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// CacheTTL is how long a successful validation result is served from cache
+	CacheTTL time.Duration
+	// FallbackGraceDays is the expiry reported for organizations assumed valid when the server answers 5xx
+	FallbackGraceDays int
+	// LicenseFile is the path of a signed offline license used instead of the license server.
+	// PublicKeys holds the trusted Ed25519 keys, by key ID, used to verify it.
+	LicenseFile string
+	PublicKeys  map[string]ed25519.PublicKey
+	// SnapshotFile persists the last successful result of each organization so it can be used after a restart
+	// while the license server is unreachable, for at most SnapshotMaxAge after it was validated.
+	SnapshotFile   string
+	SnapshotMaxAge time.Duration
+	// DiscoverOrganizations replaces OrganizationIDs with the organizations the license key entitles,
+	// asked to the license provider on every validation. A non-empty OrganizationFilter restricts them.
+	DiscoverOrganizations bool
+	OrganizationFilter    []string
+	// MaxConcurrency bounds how many organizations are validated in parallel and
+	// OrganizationTimeout bounds the validation of each one
+	MaxConcurrency      int
+	OrganizationTimeout time.Duration
+	// MeterProvider receives the license client metrics; nil disables them
+	MeterProvider metric.MeterProvider
+	// TracerProvider receives the license client spans; nil disables them
+	TracerProvider trace.TracerProvider
+	// Provider replaces the license server and the offline license file as the source of truth when set
+	Provider Provider
+	// PanicOnFailure restores the legacy behavior of panicking when startup validation fails
+	// instead of returning an error. Kept only for compatibility with older integrations.
+	PanicOnFailure bool
 }
 
 // Validate checks if the configuration is valid
@@ -20,9 +72,73 @@ func (c *ClientConfig) Validate() error {
 		return errors.New("application name is required")
 	}
 
-	if len(c.OrganizationIDs) == 0 {
+	if len(c.OrganizationIDs) == 0 && !c.DiscoverOrganizations {
 		return errors.New("at least one organization ID is required")
 	}
 
+	if err := validateBaseURL(c.BaseURL); err != nil {
+		return err
+	}
+
+	if c.HTTPTimeout <= 0 {
+		return errors.New("HTTP timeout must be positive")
+	}
+
+	if c.RefreshInterval <= 0 {
+		return errors.New("refresh interval must be positive")
+	}
+
+	if c.MaxRetries < 1 {
+		return errors.New("max retries must be at least 1")
+	}
+
+	if c.RetryBackoff < 0 {
+		return errors.New("retry backoff must not be negative")
+	}
+
+	if c.CacheTTL <= 0 {
+		return errors.New("cache TTL must be positive")
+	}
+
+	if c.FallbackGraceDays < 0 {
+		return errors.New("fallback grace days must not be negative")
+	}
+
+	if c.LicenseFile != "" && len(c.PublicKeys) == 0 {
+		return errors.New("at least one public key is required to verify the license file")
+	}
+
+	if err := ValidatePublicKeys(c.PublicKeys); err != nil {
+		return err
+	}
+
+	if c.MaxConcurrency < 1 {
+		return errors.New("max concurrency must be at least 1")
+	}
+
+	if c.OrganizationTimeout <= 0 {
+		return errors.New("organization timeout must be positive")
+	}
+
+	if c.SnapshotFile != "" && c.LicenseKey == "" {
+		return errors.New("license key is required to protect the license snapshot")
+	}
+
+	if c.SnapshotMaxAge <= 0 {
+		return errors.New("snapshot max age must be positive")
+	}
+
+	return nil
+}
+
+// ValidatePublicKeys checks that every license file key is an Ed25519 public key, since ed25519.Verify panics
+// on keys of another size
+func ValidatePublicKeys(keys map[string]ed25519.PublicKey) error {
+	for keyID, key := range keys {
+		if len(key) != ed25519.PublicKeySize {
+			return fmt.Errorf("public key %q must be %d bytes, got %d", keyID, ed25519.PublicKeySize, len(key))
+		}
+	}
+
 	return nil
 }
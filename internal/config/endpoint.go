@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+)
+
+// profileURLs maps the named profiles to their license server URL
+var profileURLs = map[string]string{
+	cn.ProfileProd:    cn.ProdLicenseGatewayBaseURL,
+	cn.ProfileDev:     cn.DevLicenseGatewayBaseURL,
+	cn.ProfileStaging: cn.StagingLicenseGatewayBaseURL,
+}
+
+// ResolveBaseURL fills BaseURL when it was not set explicitly.
+// Precedence: explicit BaseURL, explicit Profile, LICENSE_URL, LICENSE_PROFILE, IS_DEVELOPMENT, production.
+func (c *ClientConfig) ResolveBaseURL() error {
+	switch {
+	case c.BaseURL != "":
+		c.Profile = cn.ProfileCustom
+	case c.Profile != "":
+		return c.applyProfile(c.Profile)
+	case os.Getenv(cn.EnvLicenseURL) != "":
+		return c.applyProfile(cn.ProfileCustom)
+	case os.Getenv(cn.EnvLicenseProfile) != "":
+		return c.applyProfile(os.Getenv(cn.EnvLicenseProfile))
+	case os.Getenv(cn.EnvIsDevelopment) == "true":
+		return c.applyProfile(cn.ProfileDev)
+	default:
+		return c.applyProfile(cn.ProfileProd)
+	}
+
+	c.BaseURL = strings.TrimSuffix(c.BaseURL, "/")
+
+	return nil
+}
+
+// applyProfile sets BaseURL from a named profile; the custom profile reads LICENSE_URL
+func (c *ClientConfig) applyProfile(profile string) error {
+	c.Profile = strings.ToLower(strings.TrimSpace(profile))
+
+	if c.Profile == cn.ProfileCustom {
+		c.BaseURL = strings.TrimSuffix(os.Getenv(cn.EnvLicenseURL), "/")
+		if c.BaseURL == "" {
+			return fmt.Errorf("license profile %q requires %s to be set", cn.ProfileCustom, cn.EnvLicenseURL)
+		}
+
+		return nil
+	}
+
+	baseURL, ok := profileURLs[c.Profile]
+	if !ok {
+		return fmt.Errorf("unknown license profile %q", profile)
+	}
+
+	c.BaseURL = baseURL
+
+	return nil
+}
+
+// validateBaseURL checks that the license server URL is an absolute http(s) URL
+func validateBaseURL(baseURL string) error {
+	if baseURL == "" {
+		return errors.New("license server URL is required")
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid license server URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid license server URL %q: scheme must be http or https", baseURL)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("invalid license server URL %q: host is required", baseURL)
+	}
+
+	return nil
+}
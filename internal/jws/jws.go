@@ -0,0 +1,96 @@
+package jws
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Header is the protected header of a compact JWS
+type Header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"typ,omitempty"`
+}
+
+// Token is a parsed compact JWS whose signature is not verified yet
+type Token struct {
+	Header       Header
+	Payload      string
+	SigningInput []byte
+	Signature    []byte
+}
+
+// Parse splits a compact JWS and decodes its header and signature
+func Parse(token string) (Token, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Token{}, errors.New("not a compact JWS")
+	}
+
+	var h Header
+	if err := DecodeSegment(parts[0], &h); err != nil {
+		return Token{}, fmt.Errorf("invalid header: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Token{}, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	return Token{
+		Header:       h,
+		Payload:      parts[1],
+		SigningInput: []byte(parts[0] + "." + parts[1]),
+		Signature:    signature,
+	}, nil
+}
+
+// Decode decodes the payload into v. Call it only after the signature is verified.
+func (t Token) Decode(v any) error {
+	return DecodeSegment(t.Payload, v)
+}
+
+// Encode creates a compact JWS from the header and payload, signing the input with sign
+func Encode(h Header, payload any, sign func(signingInput []byte) ([]byte, error)) (string, error) {
+	encodedHeader, err := EncodeSegment(h)
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload, err := EncodeSegment(payload)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodedHeader + "." + encodedPayload
+
+	signature, err := sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// DecodeSegment decodes a base64url JSON segment into v
+func DecodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// EncodeSegment encodes v as a base64url JSON segment
+func EncodeSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,304 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwk is a single JSON Web Key
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// ParseJWKS parses a JSON Web Key Set with RSA, P-256 EC and Ed25519 keys.
+// Keys of other types, and keys not meant for signatures, are ignored.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %d: %w", i, err)
+		}
+
+		if key == nil {
+			continue
+		}
+
+		keys[k.KeyID] = key
+	}
+
+	return keys, nil
+}
+
+// publicKey decodes the key, returning nil for unsupported key types
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, nil
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !key.Curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point is not on the P-256 curve")
+		}
+
+		return key, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, nil
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, nil
+	}
+}
+
+// decodeBigInt decodes a base64url big-endian integer
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// minRefetchInterval throttles JWKS downloads triggered by unknown key IDs
+const minRefetchInterval = time.Minute
+
+// KeySet provides the keys trusted to verify tokens, combining static keys and keys read once from
+// a JWKS file with keys downloaded from a JWKS URL and refreshed periodically
+type KeySet struct {
+	local           map[string]crypto.PublicKey
+	url             string
+	httpClient      *http.Client
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	remote    map[string]crypto.PublicKey
+	fetchedAt time.Time
+	// fetching is closed when the download in flight completes; nil when none is running
+	fetching chan struct{}
+}
+
+// NewKeySet creates a key set. The JWKS file, when given, is read immediately.
+func NewKeySet(static map[string]crypto.PublicKey, jwksFile, jwksURL string, httpClient *http.Client, refreshInterval time.Duration) (*KeySet, error) {
+	keys := make(map[string]crypto.PublicKey, len(static))
+	for kid, key := range static {
+		keys[kid] = key
+	}
+
+	if jwksFile != "" {
+		data, err := os.ReadFile(jwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+
+		fileKeys, err := ParseJWKS(data)
+		if err != nil {
+			return nil, err
+		}
+
+		for kid, key := range fileKeys {
+			keys[kid] = key
+		}
+	}
+
+	if len(keys) == 0 && jwksURL == "" {
+		return nil, errors.New("at least one token verification key, JWKS file or JWKS URL is required")
+	}
+
+	return &KeySet{
+		local:           keys,
+		url:             jwksURL,
+		httpClient:      httpClient,
+		refreshInterval: refreshInterval,
+	}, nil
+}
+
+// Keys returns the keys to try for a key ID. Without a key ID every key is returned.
+// An unknown key ID triggers a throttled JWKS download so rotated keys are picked up.
+func (s *KeySet) Keys(ctx context.Context, keyID string) []crypto.PublicKey {
+	remote := s.remoteKeys(ctx, keyID)
+
+	if keyID != "" {
+		if key, ok := s.local[keyID]; ok {
+			return []crypto.PublicKey{key}
+		}
+
+		if key, ok := remote[keyID]; ok {
+			return []crypto.PublicKey{key}
+		}
+
+		return nil
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(s.local)+len(remote))
+	for _, key := range s.local {
+		keys = append(keys, key)
+	}
+
+	for _, key := range remote {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// remoteKeys returns the keys downloaded from the JWKS URL, downloading them when stale.
+// Only one download runs at a time and the lock is not held while it runs; concurrent callers
+// wait for it. The last downloaded keys are kept when a download fails.
+func (s *KeySet) remoteKeys(ctx context.Context, keyID string) map[string]crypto.PublicKey {
+	if s.url == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+
+	if !s.stale(keyID) {
+		defer s.mu.Unlock()
+
+		return s.remote
+	}
+
+	if done := s.fetching; done != nil {
+		s.mu.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		return s.remote
+	}
+
+	done := make(chan struct{})
+	s.fetching = done
+	s.mu.Unlock()
+
+	// The download is shared with concurrent callers, so it must not fail when this request is canceled
+	keys, err := s.fetch(context.WithoutCancel(ctx))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.remote = keys
+	}
+
+	s.fetchedAt = time.Now()
+	s.fetching = nil
+	close(done)
+
+	return s.remote
+}
+
+// stale reports whether the downloaded keys must be refreshed before looking up keyID. Callers hold s.mu.
+func (s *KeySet) stale(keyID string) bool {
+	age := time.Since(s.fetchedAt)
+
+	_, known := s.remote[keyID]
+	_, local := s.local[keyID]
+	unknownKey := keyID != "" && !known && !local
+
+	return s.fetchedAt.IsZero() || age >= s.refreshInterval || (unknownKey && age >= minRefetchInterval)
+}
+
+// fetch downloads and parses the JWKS
+func (s *KeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected JWKS status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJWKS(data)
+}
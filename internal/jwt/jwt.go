@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/LerianStudio/lib-license-go/internal/jws"
+)
+
+// Supported signature algorithms
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// leeway tolerates clock skew when checking the exp and nbf claims
+const leeway = time.Minute
+
+// Claims are the decoded claims of a verified token
+type Claims map[string]any
+
+// Expectations are the registered claims a token must carry. Tokens without an exp claim are
+// rejected unless AllowMissingExpiry is set.
+type Expectations struct {
+	Issuer             string
+	Audience           string
+	AllowMissingExpiry bool
+}
+
+// Verify parses a compact JWS token, verifies its signature with the keys returned for its key ID
+// and checks its time-based claims and expectations. Only RS256, ES256 and EdDSA are accepted.
+func Verify(token string, keys func(keyID string) []crypto.PublicKey, expect Expectations, now time.Time) (Claims, error) {
+	t, err := jws.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	verified := false
+
+	for _, key := range keys(t.Header.KeyID) {
+		ok, err := verifySignature(t.Header.Algorithm, key, t.SigningInput, t.Signature)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, errors.New("token signature does not match any trusted key")
+	}
+
+	var claims Claims
+	if err := t.Decode(&claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	if err := claims.validate(expect, now); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// verifySignature checks the signature with a key matching the algorithm.
+// Keys of another type are skipped so a key set can mix algorithms.
+func verifySignature(alg string, key crypto.PublicKey, signingInput, signature []byte) (bool, error) {
+	switch alg {
+	case AlgorithmRS256:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return false, nil
+		}
+
+		digest := sha256.Sum256(signingInput)
+
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil, nil
+	case AlgorithmES256:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return false, nil
+		}
+
+		digest := sha256.Sum256(signingInput)
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+
+		return ecdsa.Verify(pub, digest[:], r, s), nil
+	case AlgorithmEdDSA:
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return false, nil
+		}
+
+		return ed25519.Verify(pub, signingInput, signature), nil
+	default:
+		return false, fmt.Errorf("unsupported token algorithm %q", alg)
+	}
+}
+
+// validate checks the exp, nbf, iss and aud claims
+func (c Claims) validate(expect Expectations, now time.Time) error {
+	exp, ok := c.numericDate("exp")
+	if !ok && !expect.AllowMissingExpiry {
+		return errors.New("token has no exp claim")
+	}
+
+	if ok && now.After(exp.Add(leeway)) {
+		return errors.New("token is expired")
+	}
+
+	if nbf, ok := c.numericDate("nbf"); ok && now.Add(leeway).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+
+	if expect.Issuer != "" && c.String("iss") != expect.Issuer {
+		return fmt.Errorf("token issuer must be %q", expect.Issuer)
+	}
+
+	if expect.Audience != "" && !slices.Contains(c.audience(), expect.Audience) {
+		return fmt.Errorf("token audience must include %q", expect.Audience)
+	}
+
+	return nil
+}
+
+// String returns a string claim, or an empty string when it is missing or not a string
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+
+	return value
+}
+
+// numericDate returns a NumericDate claim as a time
+func (c Claims) numericDate(name string) (time.Time, bool) {
+	value, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(value), 0), true
+}
+
+// audience returns the aud claim, which can be a string or an array of strings
+func (c Claims) audience() []string {
+	switch aud := c["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []any:
+		values := make([]string, 0, len(aud))
+
+		for _, v := range aud {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/LerianStudio/lib-license-go/internal/jws"
+)
+
+// Sign creates a compact JWS carrying the claims, signed with RS256 for RSA keys, ES256 for P-256
+// ECDSA keys and EdDSA for Ed25519 keys
+func Sign(claims Claims, keyID string, key crypto.Signer) (string, error) {
+	alg, err := SigningAlgorithm(key)
+	if err != nil {
+		return "", err
+	}
+
+	return jws.Encode(jws.Header{Algorithm: alg, KeyID: keyID}, claims, func(signingInput []byte) ([]byte, error) {
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			digest := sha256.Sum256(signingInput)
+
+			return rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		case *ecdsa.PrivateKey:
+			digest := sha256.Sum256(signingInput)
+
+			r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+			if err != nil {
+				return nil, err
+			}
+
+			return append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...), nil
+		default:
+			return ed25519.Sign(key.(ed25519.PrivateKey), signingInput), nil
+		}
+	})
+}
+
+// SigningAlgorithm returns the algorithm Sign uses for the key, or an error when the key cannot sign tokens
+func SigningAlgorithm(key crypto.Signer) (string, error) {
+	switch k := key.(type) {
+	case nil:
+		return "", errors.New("signing key is required")
+	case *rsa.PrivateKey:
+		if k == nil {
+			return "", errors.New("signing key is required")
+		}
+
+		return AlgorithmRS256, nil
+	case *ecdsa.PrivateKey:
+		if k == nil {
+			return "", errors.New("signing key is required")
+		}
+
+		if k.Curve.Params().BitSize != 256 {
+			return "", fmt.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+		}
+
+		return AlgorithmES256, nil
+	case ed25519.PrivateKey:
+		return AlgorithmEdDSA, nil
+	default:
+		return "", fmt.Errorf("unsupported signing key type %T", key)
+	}
+}
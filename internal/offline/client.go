@@ -0,0 +1,188 @@
+package offline
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/config"
+	"github.com/LerianStudio/lib-license-go/internal/jws"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+)
+
+// algorithm is the only JWS algorithm accepted for offline licenses
+const algorithm = "EdDSA"
+
+// Document is the payload of a signed offline license
+type Document struct {
+	AppName         string    `json:"app"`
+	OrganizationIDs []string  `json:"organizations"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+	IssuedAt        time.Time `json:"issuedAt,omitempty"`
+	Trial           bool      `json:"trial,omitempty"`
+	GracePeriodDays int       `json:"gracePeriodDays,omitempty"`
+}
+
+// Client validates organizations against a signed offline license file
+type Client struct {
+	config *config.ClientConfig
+	logger log.Logger
+	now    func() time.Time
+}
+
+// New creates a new offline license client
+func New(cfg *config.ClientConfig, logger log.Logger) *Client {
+	return &Client{
+		config: cfg,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Validate validates the license of the given organization against the license file.
+// The file is read on every call so a renewed license is picked up by the next background refresh.
+func (c *Client) Validate(_ context.Context, orgID string) (model.ValidationResult, error) {
+	doc, err := c.load()
+	if err != nil {
+		return model.ValidationResult{}, err
+	}
+
+	if !slices.Contains(doc.OrganizationIDs, orgID) {
+		return model.ValidationResult{}, pkg.ForbiddenError{
+			Code:    cn.ErrOrgLicenseInvalid.Error(),
+			Title:   "Organization license is invalid",
+			Message: fmt.Sprintf("The offline license file does not cover organization ID '%s'.", orgID),
+		}
+	}
+
+	return doc.result(c.now()), nil
+}
+
+// Organizations returns the organization IDs covered by the license file
+func (c *Client) Organizations(_ context.Context) ([]string, error) {
+	doc, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.OrganizationIDs, nil
+}
+
+// load reads and verifies the license file and checks it was issued for this application
+func (c *Client) load() (Document, error) {
+	token, err := os.ReadFile(c.config.LicenseFile)
+	if err != nil {
+		c.logger.Warnf("Failed to read license file %s - error: %s", c.config.LicenseFile, err.Error())
+		return Document{}, fmt.Errorf("failed to read license file: %w", err)
+	}
+
+	doc, err := Verify(token, c.config.PublicKeys)
+	if err != nil {
+		c.logger.Debugf("License file verification failed: %v", err)
+
+		return Document{}, pkg.ForbiddenError{
+			Code:    cn.ErrInvalidLicenseFile.Error(),
+			Title:   "Invalid license file",
+			Message: "The offline license file is malformed or its signature could not be verified. Please install a license file issued for this application.",
+			Err:     err,
+		}
+	}
+
+	if doc.AppName != c.config.AppName {
+		return Document{}, pkg.ForbiddenError{
+			Code:    cn.ErrInvalidLicenseFile.Error(),
+			Title:   "Invalid license file",
+			Message: fmt.Sprintf("The offline license file was issued for application '%s', not '%s'.", doc.AppName, c.config.AppName),
+		}
+	}
+
+	return doc, nil
+}
+
+// result computes the validation result of the document at the given time
+func (d Document) result(now time.Time) model.ValidationResult {
+	if now.Before(d.ExpiresAt) {
+		return model.ValidationResult{
+			Valid:          true,
+			ExpiryDaysLeft: daysUntil(now, d.ExpiresAt),
+			IsTrial:        d.Trial,
+		}
+	}
+
+	graceEnd := d.ExpiresAt.AddDate(0, 0, d.GracePeriodDays)
+	if now.Before(graceEnd) {
+		return model.ValidationResult{
+			ExpiryDaysLeft:    daysUntil(now, graceEnd),
+			ActiveGracePeriod: true,
+			IsTrial:           d.Trial,
+		}
+	}
+
+	return model.ValidationResult{IsTrial: d.Trial}
+}
+
+// daysUntil returns the number of whole days between now and t
+func daysUntil(now, t time.Time) int {
+	return int(t.Sub(now).Hours() / 24)
+}
+
+// Verify parses a compact JWS offline license and verifies its Ed25519 signature against the trusted keys.
+// When the header names a key ID only that key is tried; otherwise every trusted key is tried.
+func Verify(token []byte, keys map[string]ed25519.PublicKey) (Document, error) {
+	t, err := jws.Parse(strings.TrimSpace(string(token)))
+	if err != nil {
+		return Document{}, fmt.Errorf("invalid license file: %w", err)
+	}
+
+	if t.Header.Algorithm != algorithm {
+		return Document{}, fmt.Errorf("unsupported algorithm %q", t.Header.Algorithm)
+	}
+
+	if !verifySignature(t.SigningInput, t.Signature, t.Header.KeyID, keys) {
+		return Document{}, errors.New("signature does not match any trusted public key")
+	}
+
+	var doc Document
+	if err := t.Decode(&doc); err != nil {
+		return Document{}, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	return doc, nil
+}
+
+// verifySignature checks the signature against the named key, or against every key when no key ID is given
+func verifySignature(signingInput, signature []byte, keyID string, keys map[string]ed25519.PublicKey) bool {
+	if keyID != "" {
+		key, ok := keys[keyID]
+
+		return ok && len(key) == ed25519.PublicKeySize && ed25519.Verify(key, signingInput, signature)
+	}
+
+	for _, key := range keys {
+		if len(key) == ed25519.PublicKeySize && ed25519.Verify(key, signingInput, signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sign produces a compact JWS offline license for the document. It is used by license tooling and tests.
+func Sign(doc Document, keyID string, key ed25519.PrivateKey) ([]byte, error) {
+	token, err := jws.Encode(jws.Header{Algorithm: algorithm, KeyID: keyID, Type: "JWT"}, doc, func(signingInput []byte) ([]byte, error) {
+		return ed25519.Sign(key, signingInput), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(token), nil
+}
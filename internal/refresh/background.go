@@ -25,6 +25,7 @@ type Manager struct {
 	logger                log.Logger
 	lastAttemptedRefresh  time.Time
 	lastSuccessfulRefresh time.Time
+	nextRefresh           time.Time
 }
 
 // New creates a new background refresh manager
@@ -47,6 +48,7 @@ func (m *Manager) Start(ctx context.Context) {
 	refreshCtx, cancel := context.WithCancel(ctx)
 	m.cancel = cancel
 	m.started = true
+	m.nextRefresh = time.Now().Add(m.refreshInterval)
 	m.mu.Unlock()
 
 	// Start a ticker to periodically refresh the license
@@ -63,9 +65,13 @@ func (m *Manager) Start(ctx context.Context) {
 
 				return
 
-			case <-ticker.C:
+			case tick := <-ticker.C:
+				m.mu.Lock()
+				m.nextRefresh = tick.Add(m.refreshInterval)
+				m.mu.Unlock()
+
 				m.logger.Debug("Running scheduled license validation")
-				m.attemptValidation(refreshCtx)
+				_ = m.attemptValidation(refreshCtx)
 			}
 		}
 	}()
@@ -86,11 +92,42 @@ func (m *Manager) Shutdown() {
 	}
 
 	m.started = false
+	m.nextRefresh = time.Time{}
 	m.logger.Debug("Background license refresh shutdown complete")
 }
 
+// LastSuccessfulRefresh returns when the last background validation succeeded, zero until one does
+func (m *Manager) LastSuccessfulRefresh() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.lastSuccessfulRefresh
+}
+
+// LastAttemptedRefresh returns when the last background validation started, zero until one does
+func (m *Manager) LastAttemptedRefresh() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.lastAttemptedRefresh
+}
+
+// NextRefresh returns when the next background validation is scheduled, zero while the refresh is stopped
+func (m *Manager) NextRefresh() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.nextRefresh
+}
+
+// Refresh runs a background validation immediately, with the same retries and outcome as a scheduled one.
+// It does not change the schedule.
+func (m *Manager) Refresh(ctx context.Context) error {
+	return m.attemptValidation(ctx)
+}
+
 // attemptValidation performs a validation with retry logic
-func (m *Manager) attemptValidation(ctx context.Context) {
+func (m *Manager) attemptValidation(ctx context.Context) error {
 	m.mu.Lock()
 	m.lastAttemptedRefresh = time.Now()
 	m.mu.Unlock()
@@ -107,4 +144,6 @@ func (m *Manager) attemptValidation(ctx context.Context) {
 	} else {
 		m.logger.Errorf("License validation failed after retries: %v", err)
 	}
+
+	return err
 }
@@ -0,0 +1,240 @@
+package snapshot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	"github.com/LerianStudio/lib-license-go/model"
+)
+
+// ErrTampered is returned when the snapshot integrity check fails
+var ErrTampered = errors.New("license snapshot integrity check failed")
+
+// Entry is the last successful validation of an organization
+type Entry struct {
+	Result      model.ValidationResult `json:"result"`
+	ValidatedAt time.Time              `json:"validatedAt"`
+}
+
+// payload is the authenticated content of the snapshot file
+type payload struct {
+	AppName string           `json:"app"`
+	Entries map[string]Entry `json:"entries"`
+}
+
+// file is the on-disk representation of the snapshot
+type file struct {
+	payload
+	MAC string `json:"mac"`
+}
+
+// Store persists the last successful validation result of each organization to a file
+// protected by an HMAC keyed with the license key, so results survive restarts without network
+type Store struct {
+	path    string
+	appName string
+	key     []byte
+	logger  log.Logger
+	mu      sync.Mutex
+	entries map[string]Entry
+	// dirty is set when entries changed since the file was last written
+	dirty bool
+}
+
+// New creates a snapshot store for the given file
+func New(path, appName, licenseKey string, logger log.Logger) *Store {
+	return &Store{
+		path:    path,
+		appName: appName,
+		key:     []byte(licenseKey),
+		logger:  logger,
+		entries: make(map[string]Entry),
+	}
+}
+
+// Load reads and verifies the snapshot file. A missing file is not an error.
+// On any failure the store starts empty.
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read license snapshot: %w", err)
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("failed to decode license snapshot: %w", err)
+	}
+
+	expected, err := s.sign(f.payload)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal([]byte(expected), []byte(f.MAC)) || f.AppName != s.appName {
+		return ErrTampered
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Entries != nil {
+		s.entries = f.Entries
+	}
+
+	s.logger.Debugf("Loaded license snapshot with %d organizations from %s", len(f.Entries), s.path)
+
+	return nil
+}
+
+// Record stores the result of a successful validation in memory. Flush persists the recorded results,
+// so validating many organizations costs a single file write.
+func (s *Store) Record(orgID string, result model.ValidationResult, validatedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[orgID] = Entry{Result: result, ValidatedAt: validatedAt.UTC()}
+	s.dirty = true
+}
+
+// Flush rewrites the snapshot file when results were recorded since the last write
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	return s.write()
+}
+
+// Remove drops the entry of an organization and rewrites the snapshot file
+func (s *Store) Remove(orgID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[orgID]; !ok {
+		return nil
+	}
+
+	delete(s.entries, orgID)
+
+	return s.write()
+}
+
+// OrganizationIDs returns, sorted, the organizations with a persisted result validated within maxAge
+func (s *Store) OrganizationIDs(maxAge time.Duration, now time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orgIDs := make([]string, 0, len(s.entries))
+
+	for orgID, entry := range s.entries {
+		if elapsed := now.Sub(entry.ValidatedAt); elapsed >= 0 && elapsed <= maxAge {
+			orgIDs = append(orgIDs, orgID)
+		}
+	}
+
+	slices.Sort(orgIDs)
+
+	return orgIDs
+}
+
+// Lookup returns the persisted result of an organization as of now, with the expiry reduced by the
+// days elapsed since it was validated. It reports false when the entry is older than maxAge or the
+// license would have expired in the meantime.
+func (s *Store) Lookup(orgID string, maxAge time.Duration, now time.Time) (model.ValidationResult, time.Time, bool) {
+	s.mu.Lock()
+	entry, ok := s.entries[orgID]
+	s.mu.Unlock()
+
+	if !ok {
+		return model.ValidationResult{}, time.Time{}, false
+	}
+
+	elapsed := now.Sub(entry.ValidatedAt)
+	if elapsed < 0 || elapsed > maxAge {
+		return model.ValidationResult{}, entry.ValidatedAt, false
+	}
+
+	result := entry.Result
+	result.ExpiryDaysLeft -= int(elapsed.Hours() / 24)
+
+	if result.ExpiryDaysLeft < 0 {
+		return model.ValidationResult{}, entry.ValidatedAt, false
+	}
+
+	return result, entry.ValidatedAt, true
+}
+
+// write atomically replaces the snapshot file with the current entries. The caller must hold s.mu.
+func (s *Store) write() error {
+	p := payload{AppName: s.appName, Entries: s.entries}
+
+	mac, err := s.sign(p)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(file{payload: p, MAC: mac})
+	if err != nil {
+		return fmt.Errorf("failed to encode license snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create license snapshot: %w", err)
+	}
+
+	// Remove the temporary file unless it was renamed over the snapshot
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write license snapshot: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync license snapshot: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close license snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace license snapshot: %w", err)
+	}
+
+	s.dirty = false
+
+	return nil
+}
+
+// sign computes the hex HMAC-SHA256 of the payload keyed with the license key
+func (s *Store) sign(p payload) (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode license snapshot: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(data)
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,226 @@
+// Package telemetry holds the OpenTelemetry instrumentation of the license client
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/lib-license-go/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+// ScopeName is the instrumentation scope of the license client metrics and spans
+const ScopeName = "github.com/LerianStudio/lib-license-go"
+
+// Validation outcomes recorded by RecordValidation
+const (
+	OutcomeValid    = "valid"
+	OutcomeGrace    = "grace_period"
+	OutcomeInvalid  = "invalid"
+	OutcomeRejected = "rejected"
+	OutcomeFallback = "fallback"
+	OutcomeError    = "error"
+)
+
+// Fallback sources recorded by RecordFallback
+const (
+	FallbackCache    = "cache"
+	FallbackSnapshot = "snapshot"
+	FallbackGrace    = "grace_days"
+)
+
+// Attribute keys of the license client metrics and spans
+const (
+	OrganizationIDKey = attribute.Key("license.organization.id")
+	OutcomeKey        = attribute.Key("license.outcome")
+	OperationKey      = attribute.Key("license.operation")
+	FallbackSourceKey = attribute.Key("license.fallback.source")
+	CacheResultKey    = attribute.Key("license.cache.result")
+	StatusCodeKey     = semconv.HTTPResponseStatusCodeKey
+	ErrorTypeKey      = semconv.ErrorTypeKey
+)
+
+// Metrics records the license client metrics. A nil *Metrics records nothing.
+type Metrics struct {
+	validations metric.Int64Counter
+	gateway     metric.Float64Histogram
+	cache       metric.Int64Counter
+	fallbacks   metric.Int64Counter
+
+	mu      sync.Mutex
+	results map[string]model.ValidationResult
+}
+
+// NewMetrics creates the license client instruments on the meter provider. lastRefresh reports the time of
+// the last successful background refresh, zero until one succeeds.
+func NewMetrics(provider metric.MeterProvider, lastRefresh func() time.Time) (*Metrics, error) {
+	meter := provider.Meter(ScopeName)
+
+	m := &Metrics{results: make(map[string]model.ValidationResult)}
+
+	var err, instrumentErr error
+
+	m.validations, instrumentErr = meter.Int64Counter("license.validations",
+		metric.WithDescription("License validations by outcome"),
+		metric.WithUnit("{validation}"))
+	err = errors.Join(err, instrumentErr)
+
+	m.gateway, instrumentErr = meter.Float64Histogram("license.gateway.duration",
+		metric.WithDescription("Duration of license gateway requests"),
+		metric.WithUnit("s"))
+	err = errors.Join(err, instrumentErr)
+
+	m.cache, instrumentErr = meter.Int64Counter("license.cache.lookups",
+		metric.WithDescription("License cache lookups by result"),
+		metric.WithUnit("{lookup}"))
+	err = errors.Join(err, instrumentErr)
+
+	m.fallbacks, instrumentErr = meter.Int64Counter("license.fallbacks",
+		metric.WithDescription("Validations answered from a fallback while the license provider was unavailable"),
+		metric.WithUnit("{fallback}"))
+	err = errors.Join(err, instrumentErr)
+
+	expiry, instrumentErr := meter.Int64ObservableGauge("license.expiry.days",
+		metric.WithDescription("Days until the license of an organization expires, or until its grace period ends"),
+		metric.WithUnit("d"))
+	err = errors.Join(err, instrumentErr)
+
+	grace, instrumentErr := meter.Int64ObservableGauge("license.grace_period.active",
+		metric.WithDescription("1 while the license of an organization is in its grace period"))
+	err = errors.Join(err, instrumentErr)
+
+	refreshed, instrumentErr := meter.Float64ObservableGauge("license.refresh.last_success",
+		metric.WithDescription("Unix time of the last successful background refresh"),
+		metric.WithUnit("s"))
+	err = errors.Join(err, instrumentErr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for orgID, result := range m.results {
+			attrs := metric.WithAttributes(OrganizationIDKey.String(orgID))
+
+			o.ObserveInt64(expiry, int64(result.ExpiryDaysLeft), attrs)
+			o.ObserveInt64(grace, boolGauge(result.ActiveGracePeriod), attrs)
+		}
+
+		if at := lastRefresh(); !at.IsZero() {
+			o.ObserveFloat64(refreshed, float64(at.UnixNano())/float64(time.Second))
+		}
+
+		return nil
+	}, expiry, grace, refreshed)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// RecordValidation counts a validation of an organization. statusCode is the HTTP status answered by the
+// license gateway when it rejected the request, or zero. Accepted results feed the expiry gauges; any other
+// outcome clears them, so a rejected organization does not keep reporting its last accepted license.
+func (m *Metrics) RecordValidation(orgID, outcome string, statusCode int, result model.ValidationResult) {
+	if m == nil {
+		return
+	}
+
+	attrs := []attribute.KeyValue{OutcomeKey.String(outcome)}
+	if statusCode != 0 {
+		attrs = append(attrs, StatusCodeKey.Int(statusCode))
+	}
+
+	m.validations.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if result.Valid || result.ActiveGracePeriod {
+		m.results[orgID] = result
+	} else {
+		delete(m.results, orgID)
+	}
+}
+
+// RecordGatewayRequest records the duration of a license gateway request. statusCode is zero when no
+// response was received, in which case err names the failure.
+func (m *Metrics) RecordGatewayRequest(ctx context.Context, operation string, duration time.Duration, statusCode int, err error) {
+	if m == nil {
+		return
+	}
+
+	attrs := []attribute.KeyValue{OperationKey.String(operation)}
+
+	switch {
+	case statusCode != 0:
+		attrs = append(attrs, StatusCodeKey.Int(statusCode))
+	case err != nil:
+		attrs = append(attrs, ErrorTypeKey.String(ErrorType(err)))
+	}
+
+	m.gateway.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+}
+
+// RecordCacheLookup counts a license cache hit or miss
+func (m *Metrics) RecordCacheLookup(hit bool) {
+	if m == nil {
+		return
+	}
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	m.cache.Add(context.Background(), 1, metric.WithAttributes(CacheResultKey.String(result)))
+}
+
+// RecordFallback counts a validation answered from a fallback source
+func (m *Metrics) RecordFallback(source string) {
+	if m == nil {
+		return
+	}
+
+	m.fallbacks.Add(context.Background(), 1, metric.WithAttributes(FallbackSourceKey.String(source)))
+}
+
+// Forget stops reporting the expiry gauges of a removed organization
+func (m *Metrics) Forget(orgID string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	delete(m.results, orgID)
+	m.mu.Unlock()
+}
+
+// ErrorType names the failure of a request for the error.type attribute
+func ErrorType(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "transport"
+	}
+}
+
+// boolGauge converts a flag to a gauge value
+func boolGauge(flag bool) int64 {
+	if flag {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,37 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Span attribute keys of the license client; the license key is never recorded
+const (
+	AttemptKey  = attribute.Key("license.attempt")
+	CacheHitKey = attribute.Key("license.cache.hit")
+)
+
+// NewTracer returns the tracer of the license client, a no-op tracer when provider is nil
+func NewTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+
+	return provider.Tracer(ScopeName)
+}
+
+// EndSpan records the outcome on the span, marks it as failed when err is not nil and ends it
+func EndSpan(span trace.Span, outcome string, err error) {
+	if outcome != "" {
+		span.SetAttributes(OutcomeKey.String(outcome))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
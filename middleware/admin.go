@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/pkg"
+	pkgHTTP "github.com/LerianStudio/lib-license-go/pkg/net/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// Actions run by a POST to the admin handler, selected with the action query parameter
+const (
+	// AdminActionRefresh starts re-validating every organization in the background
+	AdminActionRefresh = "refresh"
+	// AdminActionInvalidate evicts the cached result of the organization in the organizationId query parameter
+	AdminActionInvalidate = "invalidate"
+)
+
+// AdminAuthorizer decides whether a request may use the admin handler; it returns an error to reject it
+type AdminAuthorizer func(r *http.Request) error
+
+// HTTPAdminHandler creates a net/http handler that lets support engineers see what the client believes about
+// each license, e.g. mounted at /internal/license. GET renders Status as JSON and POST runs an action:
+//
+//	POST /internal/license?action=refresh
+//	POST /internal/license?action=invalidate&organizationId=<id>
+//
+// and then renders the status. A refresh runs in the background, answers 202 Accepted and never terminates
+// the application; poll GET to see its outcome. Every request is passed to authorize; without an authorizer
+// every request is rejected with LCS-0020. Mount it on an internal listener only.
+func (c *LicenseClient) HTTPAdminHandler(authorize AdminAuthorizer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodPost {
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		// NewLicenseClient returns nil for an invalid configuration
+		if c == nil || c.validator == nil {
+			pkgHTTP.WriteError(w, pkg.ValidateInternalError(errors.New("license client is not initialized"), ""))
+			return
+		}
+
+		if err := c.authorizeAdmin(r, authorize); err != nil {
+			pkgHTTP.WriteError(w, err)
+			return
+		}
+
+		statusCode := http.StatusOK
+
+		if r.Method == http.MethodPost {
+			var err error
+			if statusCode, err = c.runAdminAction(r); err != nil {
+				pkgHTTP.WriteError(w, err)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(statusCode)
+
+		_ = json.NewEncoder(w).Encode(c.Status())
+	})
+}
+
+// AdminHandler creates a Fiber handler for the admin endpoint, e.g. app.All("/internal/license", ...).
+// It behaves like HTTPAdminHandler; authorize receives the request converted to net/http.
+func (c *LicenseClient) AdminHandler(authorize AdminAuthorizer) fiber.Handler {
+	return adaptor.HTTPHandler(c.HTTPAdminHandler(authorize))
+}
+
+// authorizeAdmin runs the authorizer of the admin handler. Without one, every request is rejected.
+func (c *LicenseClient) authorizeAdmin(r *http.Request, authorize AdminAuthorizer) error {
+	err := cn.ErrAdminForbidden
+	if authorize != nil {
+		err = authorize(r)
+	}
+
+	if err != nil {
+		c.GetLogger().Warnf("License admin request rejected: %v", err)
+		return pkg.ValidateBusinessError(cn.ErrAdminForbidden, "")
+	}
+
+	return nil
+}
+
+// runAdminAction runs the action selected by a POST to the admin handler and returns the status code
+// of the response
+func (c *LicenseClient) runAdminAction(r *http.Request) (int, error) {
+	action := r.URL.Query().Get(cn.AdminActionParam)
+
+	switch action {
+	case AdminActionRefresh:
+		if c.TriggerRefresh() {
+			c.GetLogger().Info("License refresh requested through the admin handler")
+		} else {
+			c.GetLogger().Info("License refresh requested through the admin handler is already running")
+		}
+
+		return http.StatusAccepted, nil
+	case AdminActionInvalidate:
+		orgID := strings.TrimSpace(r.URL.Query().Get(cn.AdminOrganizationIDParam))
+		if orgID == "" {
+			return 0, pkg.ValidateBusinessError(cn.ErrInvalidAdminRequest, "", fmt.Sprintf("the %s query parameter is required", cn.AdminOrganizationIDParam))
+		}
+
+		if err := c.InvalidateCache(orgID); err != nil {
+			return 0, pkg.ValidateBusinessError(cn.ErrUnknownOrgIDHeader, "", orgID)
+		}
+
+		c.GetLogger().Infof("License cache of org %s invalidated through the admin handler", orgID)
+
+		return http.StatusOK, nil
+	default:
+		return 0, pkg.ValidateBusinessError(cn.ErrInvalidAdminRequest, "", fmt.Sprintf("unknown action '%s'", action))
+	}
+}
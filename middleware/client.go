@@ -2,15 +2,16 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	libLicense "github.com/LerianStudio/lib-commons/commons/license"
 	"github.com/LerianStudio/lib-commons/commons/log"
 	cn "github.com/LerianStudio/lib-license-go/constant"
 	"github.com/LerianStudio/lib-license-go/model"
-	"github.com/LerianStudio/lib-license-go/pkg"
 	"github.com/LerianStudio/lib-license-go/validation"
 )
 
@@ -21,6 +22,27 @@ type LicenseClient struct {
 	// initOnce ensures startup validation and background refresh happen only once
 	// even when both HTTP middleware and gRPC interceptors are used
 	initOnce sync.Once
+	// startMu guards startErr and startReport, the outcome of the startup validation performed inside
+	// initOnce, which changes when a failed startup recovers, and stopRecovery, which stops its retries
+	startMu      sync.RWMutex
+	started      bool
+	startErr     error
+	startReport  validation.Report
+	stopRecovery context.CancelFunc
+	// orgResolver extracts the organization ID from Fiber requests; nil reads the X-Organization-ID header
+	orgResolver OrgResolver
+	// grpcOrgResolver extracts the organization ID from gRPC calls; nil reads the X-Organization-ID metadata
+	grpcOrgResolver GRPCOrgResolver
+	// httpOrgResolver extracts the organization ID for HTTPMiddleware; nil reads the X-Organization-ID header
+	httpOrgResolver HTTPOrgResolver
+	// streamRevalidateInterval and streamRevalidateMessages control how often open gRPC streams re-check
+	// the license; zero disables each trigger
+	streamRevalidateInterval time.Duration
+	streamRevalidateMessages int
+	// exemptions lists the requests that skip the license check; nil applies DefaultExemptions
+	exemptions *Exemptions
+	// statusHeaders configures the license status response headers; nil disables them
+	statusHeaders *StatusHeaders
 }
 
 // ValidateInitialization checks if the client is correctly initialized.
@@ -54,10 +76,14 @@ func (c *LicenseClient) validateClientInitialization(operation string) error {
 	return nil
 }
 
-// NewLicenseClient creates a new license client with middleware capabilities
-func NewLicenseClient(appID, licenseKey, orgIDs string, logger *log.Logger) *LicenseClient {
+// Option customizes the license client; see the validation.With* functions
+type Option = validation.Option
+
+// NewLicenseClient creates a new license client with middleware capabilities.
+// It returns nil when the configuration (including any options) is invalid.
+func NewLicenseClient(appID, licenseKey, orgIDs string, logger *log.Logger, opts ...Option) *LicenseClient {
 	// Create validation client (handles logger internally)
-	validator, err := validation.New(appID, licenseKey, orgIDs, logger)
+	validator, err := validation.New(appID, licenseKey, orgIDs, logger, opts...)
 	if err != nil {
 		return nil
 	}
@@ -67,35 +93,142 @@ func NewLicenseClient(appID, licenseKey, orgIDs string, logger *log.Logger) *Lic
 	}
 }
 
-// validateGlobalLicenseOnStartup validates the global license during application start.
-// Panics if the license is not valid so that the app never starts with an invalid license.
-func (c *LicenseClient) validateGlobalLicenseOnStartup(ctx context.Context) {
-	l := c.validator.GetLogger()
+// Start validates the configured licenses and kicks off the background refresh.
+// Unlike the implicit startup performed by Middleware() and the gRPC interceptors, it returns an error
+// instead of panicking when no organization has a valid license, so the application can log context,
+// flush telemetry and exit cleanly. The returned error wraps cn.ErrNoValidLicenses or cn.ErrNoOrganizationIDs.
+// A handler installed with SetTerminationHandler or SetTerminationReasonHandler is also invoked, once, with
+// the startup failure.
+// It is safe to call multiple times; validation happens only once and later calls return the current outcome.
+//
+// A failed startup does not stop the application: requests are rejected with the startup error code while the
+// validation is retried in the background, with the retry backoff doubling up to
+// cn.DefaultStartupRetryMaxBackoffSeconds. The first successful retry clears the failure and starts the
+// background refresh. Use Ready as a readiness probe so the orchestrator stops routing traffic to, or restarts,
+// an instance that cannot validate its license.
+func (c *LicenseClient) Start(ctx context.Context) error {
+	if err := c.validateClientInitialization("start license client"); err != nil {
+		return err
+	}
 
-	result, err := c.validator.ValidateOrganizationWithCache(ctx, cn.GlobalPluginValue)
-	if err != nil {
-		l.Errorf("License validation failed: %v", err)
-		panic(fmt.Sprintf("License validation failed: %s", err.Error()))
+	c.initOnce.Do(func() {
+		c.runStartupValidation(ctx)
+	})
+
+	return c.Ready()
+}
+
+// Ready returns nil once the startup validation succeeded, or the startup validation error while it is being
+// retried. It is meant for readiness probes; it returns an error until Start (or the first request) runs.
+func (c *LicenseClient) Ready() error {
+	if err := c.validateClientInitialization("check license readiness"); err != nil {
+		return err
 	}
 
-	if !result.Valid && !result.ActiveGracePeriod {
-		l.Error("License is invalid")
-		panic("No valid license found")
+	c.startMu.RLock()
+	defer c.startMu.RUnlock()
+
+	if !c.started {
+		return errors.New("license startup validation has not run")
 	}
 
-	c.logLicenseStatus(result, cn.GlobalPluginValue)
+	return c.startErr
 }
 
-// validateMultiOrgLicensesOnStartup validates each configured organization licence at startup.
-// Panics if no valid licences are found so that the app never starts unlicensed.
-func (c *LicenseClient) validateMultiOrgLicensesOnStartup(ctx context.Context) {
-	l := c.validator.GetLogger()
+// runStartupValidation validates all configured organizations and starts the background refresh on success,
+// or the startup retries on failure
+func (c *LicenseClient) runStartupValidation(ctx context.Context) {
+	report, err := c.validator.ValidateStartup(ctx)
+	c.recordStartup(report, err)
 
-	_, err := c.validator.ValidateAllOrganizations(ctx)
 	if err != nil {
-		l.Debugf("error in validateMultiOrgLicensesOnStartup: %v", err)
-		panic("No valid licenses found for any organization")
+		c.validator.GetLogger().Errorf("License startup validation failed: %v", err)
+
+		// A custom termination handler decides how the application reacts, e.g. a graceful shutdown
+		if c.validator.HasTerminationHandler() {
+			c.validator.Terminate(validation.NewTerminationReason(model.TerminationCauseStartupFailure, report, err))
+		}
+
+		recoveryCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+		c.startMu.Lock()
+		c.stopRecovery = cancel
+		c.startMu.Unlock()
+
+		go c.retryStartup(recoveryCtx)
+
+		return
 	}
+
+	// Kick-off background refresh regardless of mode, detached from the caller's cancellation
+	go c.validator.StartBackgroundRefresh(context.WithoutCancel(ctx))
+}
+
+// retryStartup retries a failed startup validation until it succeeds or the client is shut down
+func (c *LicenseClient) retryStartup(ctx context.Context) {
+	l := c.validator.GetLogger()
+	backoff := c.validator.RetryBackoff()
+	maxBackoff := cn.DefaultStartupRetryMaxBackoffSeconds * time.Second
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		report, err := c.validator.ValidateStartup(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.recordStartup(report, err)
+
+		if err == nil {
+			l.Info("License startup validation recovered, serving requests")
+			c.validator.StartBackgroundRefresh(ctx)
+
+			return
+		}
+
+		l.Debugf("License startup validation retry failed: %v", err)
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// recordStartup records the outcome of a startup validation and logs the organizations it denies
+func (c *LicenseClient) recordStartup(report validation.Report, err error) {
+	for _, org := range report.Organizations {
+		if !org.Valid() {
+			c.logLicenseStatus(org.Result, org.OrganizationID)
+		}
+	}
+
+	c.startMu.Lock()
+	defer c.startMu.Unlock()
+
+	c.started = true
+	c.startReport = report
+	c.startErr = err
+}
+
+// startupFailure returns the LCS error code recorded when startup validation failed,
+// or nil when the client started successfully.
+func (c *LicenseClient) startupFailure() error {
+	c.startMu.RLock()
+	defer c.startMu.RUnlock()
+
+	if c.startErr == nil {
+		return nil
+	}
+
+	var startupErr *validation.StartupError
+	if errors.As(c.startErr, &startupErr) {
+		return startupErr.Code
+	}
+
+	return cn.ErrNoValidLicenses
 }
 
 // logLicenseStatus delegates license status logging to the validation client
@@ -129,6 +262,14 @@ func (c *LicenseClient) SetHTTPClient(client *http.Client) {
 	}
 }
 
+// SetPanicOnFailure restores the legacy behavior of panicking when startup validation fails.
+// When disabled (the default), Middleware() and the interceptors reject every request with LCS-0003 instead.
+func (c *LicenseClient) SetPanicOnFailure(enabled bool) {
+	if c != nil && c.validator != nil {
+		c.validator.SetPanicOnFailure(enabled)
+	}
+}
+
 // SetTerminationHandler allows customizing how the application terminates when license validation fails
 func (c *LicenseClient) SetTerminationHandler(handler func(reason string)) {
 	if c != nil && c.validator != nil {
@@ -136,15 +277,130 @@ func (c *LicenseClient) SetTerminationHandler(handler func(reason string)) {
 	}
 }
 
-// ShutdownBackgroundRefresh stops the background refresh process
-func (c *LicenseClient) ShutdownBackgroundRefresh() {
+// SetTerminationReasonHandler is like SetTerminationHandler but the handler receives the structured
+// reason (LCS code, cause, organization IDs and last server response)
+func (c *LicenseClient) SetTerminationReasonHandler(handler func(reason model.TerminationReason)) {
 	if c != nil && c.validator != nil {
-		c.validator.ShutdownBackgroundRefresh()
+		c.validator.SetTerminationReasonHandler(handler)
 	}
 }
 
-// GetLogger returns the logger used by the client
+// AddOrganization validates a new organization and starts serving it without a restart.
+// It returns an error, and the organization stays unknown (LCS-0011), when its license is not valid.
+func (c *LicenseClient) AddOrganization(ctx context.Context, orgID string) error {
+	if err := c.validateClientInitialization("add organization"); err != nil {
+		return err
+	}
+
+	return c.validator.AddOrganization(ctx, orgID)
+}
+
+// RemoveOrganization stops serving an organization and evicts its cached license results
+func (c *LicenseClient) RemoveOrganization(orgID string) error {
+	if err := c.validateClientInitialization("remove organization"); err != nil {
+		return err
+	}
+
+	return c.validator.RemoveOrganization(orgID)
+}
+
+// Organizations returns the organization IDs currently served by the client
+func (c *LicenseClient) Organizations() []string {
+	if c == nil || c.validator == nil {
+		return []string{}
+	}
+
+	return c.validator.GetOrganizationIDs()
+}
+
+// SetOrganizationEventHandler registers a handler notified when organizations are added, rejected or removed
+func (c *LicenseClient) SetOrganizationEventHandler(handler func(event model.OrganizationEvent)) {
+	if c != nil && c.validator != nil {
+		c.validator.SetOrganizationEventHandler(handler)
+	}
+}
+
+// OnStateChange subscribes handler to the license state transitions of every organization, e.g. to page
+// on-call when a license is revoked or to flip feature flags when a trial ends
+func (c *LicenseClient) OnStateChange(handler func(transition model.StateTransition)) {
+	if c != nil && c.validator != nil {
+		c.validator.OnStateChange(handler)
+	}
+}
+
+// State returns the license state of an organization (cn.GlobalPluginValue in global mode)
+func (c *LicenseClient) State(orgID string) model.LicenseState {
+	if c == nil || c.validator == nil {
+		return model.StateUnknown
+	}
+
+	return c.validator.State(orgID)
+}
+
+// Status returns a snapshot of the license client: the last result, state and source of each organization,
+// the background refresh timestamps, the mode and the license server endpoint
+func (c *LicenseClient) Status() model.ClientStatus {
+	if c == nil || c.validator == nil {
+		return model.ClientStatus{GeneratedAt: time.Now()}
+	}
+
+	return c.validator.Status()
+}
+
+// Refresh re-validates every organization now, like a scheduled background refresh: with the same
+// retries, and terminating the application when the license server rejects every organization
+func (c *LicenseClient) Refresh(ctx context.Context) error {
+	if err := c.validateClientInitialization("refresh licenses"); err != nil {
+		return err
+	}
+
+	return c.validator.Refresh(ctx)
+}
+
+// TriggerRefresh starts re-validating every organization in the background and returns immediately.
+// Unlike Refresh it never terminates the application. It returns false when a triggered refresh is
+// still running or the client is not initialized.
+func (c *LicenseClient) TriggerRefresh() bool {
+	if err := c.validateClientInitialization("trigger license refresh"); err != nil {
+		return false
+	}
+
+	return c.validator.TriggerRefresh()
+}
+
+// InvalidateCache evicts the cached license result of an organization, so its next request is
+// validated against the license server
+func (c *LicenseClient) InvalidateCache(orgID string) error {
+	if err := c.validateClientInitialization("invalidate license cache"); err != nil {
+		return err
+	}
+
+	return c.validator.InvalidateCache(orgID)
+}
+
+// ShutdownBackgroundRefresh stops the background refresh process and the retries of a failed startup
+func (c *LicenseClient) ShutdownBackgroundRefresh() {
+	if c == nil || c.validator == nil {
+		return
+	}
+
+	c.startMu.Lock()
+	if c.stopRecovery != nil {
+		c.stopRecovery()
+		c.stopRecovery = nil
+	}
+	c.startMu.Unlock()
+
+	c.validator.ShutdownBackgroundRefresh()
+}
+
+// GetLogger returns the logger used by the client, or a logger discarding everything when the client
+// is not initialized
 func (c *LicenseClient) GetLogger() log.Logger {
+	if c == nil || c.validator == nil {
+		return &log.NoneLogger{}
+	}
+
 	return c.validator.GetLogger()
 }
 
@@ -158,23 +414,22 @@ func (c *LicenseClient) GetLicenseManagerShutdown() *libLicense.ManagerShutdown
 }
 
 // startupValidation performs license validation at application startup and initializes background refresh.
-// It is safe to call multiple times as it uses sync.Once internally to ensure validation happens only once.
+// It is safe to call multiple times as it delegates to Start, which validates only once.
 // Panics if the client is nil or misconfigured to prevent running without license validation.
+// On validation failure a custom termination handler is invoked once by Start; without one, the default
+// handler panics only when the PanicOnFailure compatibility mode is enabled.
 func (c *LicenseClient) startupValidation() {
-	// Validate client initialization before entering the once block
+	// Validate client initialization before starting
 	// This prevents silently skipping validation on misconfigured clients
 	c.ValidateInitialization("perform startup validation")
 
-	c.initOnce.Do(func() {
-		bgCtx := context.Background()
-		if c.validator.IsGlobal {
-			c.validateGlobalLicenseOnStartup(bgCtx)
-		} else {
-			c.validateMultiOrgLicensesOnStartup(bgCtx)
-		}
-		// Kick-off background refresh regardless of mode
-		go c.validator.StartBackgroundRefresh(bgCtx)
-	})
+	if err := c.Start(context.Background()); err != nil && c.validator.PanicOnFailure() && !c.validator.HasTerminationHandler() {
+		c.startMu.RLock()
+		report := c.startReport
+		c.startMu.RUnlock()
+
+		c.validator.Terminate(validation.NewTerminationReason(model.TerminationCauseStartupFailure, report, err))
+	}
 }
 
 // validateOrganizationID validates if the provided organization ID is valid
@@ -188,7 +443,7 @@ func (c *LicenseClient) validateOrganizationID(ctx context.Context, orgID string
 		return model.ValidationResult{}, cn.ErrMissingOrgIDHeader
 	}
 
-	if !pkg.ContainsOrganizationID(c.validator.GetOrganizationIDs(), orgID) {
+	if !c.validator.HasOrganization(orgID) {
 		return model.ValidationResult{}, cn.ErrUnknownOrgIDHeader
 	}
 
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Keys under which the HTTP middlewares store an accepted multi-organization request in fiber.Ctx.Locals
+// and in the Gin and Echo contexts
+const (
+	// OrganizationIDKey holds the organization ID as a string
+	OrganizationIDKey = "license.organizationID"
+	// DecisionKey holds the Decision
+	DecisionKey = "license.decision"
+)
+
+// Decision is the license decision for a request accepted in multi-organization mode. Handlers use it
+// to show trial or expiry banners, restrict trial features or log the license state.
+type Decision struct {
+	OrganizationID string
+	Result         model.ValidationResult
+
+	// global marks a request accepted in global mode, which carries no organization
+	global bool
+}
+
+// decisionContextKey is the context key of the Decision
+type decisionContextKey struct{}
+
+// organizationIDContextKey is the context key of an organization ID set with ContextWithOrganizationID
+type organizationIDContextKey struct{}
+
+// ContextWithDecision returns a copy of ctx carrying the decision
+func ContextWithDecision(ctx context.Context, decision Decision) context.Context {
+	return context.WithValue(ctx, decisionContextKey{}, decision)
+}
+
+// FromContext returns the license decision stored by the HTTP middlewares (in the request context, or
+// fiber.Ctx.UserContext) and by the gRPC server interceptors
+func FromContext(ctx context.Context) (Decision, bool) {
+	decision, ok := ctx.Value(decisionContextKey{}).(Decision)
+
+	return decision, ok
+}
+
+// FromLocals returns the license decision stored by the Fiber middleware
+func FromLocals(c *fiber.Ctx) (Decision, bool) {
+	decision, ok := c.Locals(DecisionKey).(Decision)
+
+	return decision, ok
+}
+
+// ContextWithOrganizationID returns a copy of ctx carrying the organization ID
+func ContextWithOrganizationID(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, organizationIDContextKey{}, orgID)
+}
+
+// OrganizationIDFromContext returns the organization ID set with ContextWithOrganizationID, or else the
+// one of the license decision stored by the middlewares and interceptors
+func OrganizationIDFromContext(ctx context.Context) (string, bool) {
+	if orgID, ok := ctx.Value(organizationIDContextKey{}).(string); ok && orgID != "" {
+		return orgID, true
+	}
+
+	decision, ok := FromContext(ctx)
+
+	return decision.OrganizationID, ok && decision.OrganizationID != ""
+}
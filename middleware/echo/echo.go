@@ -0,0 +1,39 @@
+// Package echo adapts the license middleware to the Echo framework
+package echo
+
+import (
+	"github.com/LerianStudio/lib-license-go/middleware"
+	pkgHTTP "github.com/LerianStudio/lib-license-go/pkg/net/http"
+	labEcho "github.com/labstack/echo/v4"
+)
+
+// Middleware creates an Echo middleware that validates the license like the Fiber middleware.
+// The organization ID is resolved with the client's HTTPOrgResolver (the X-Organization-ID header by
+// default). It and the license decision are stored under middleware.OrganizationIDKey and
+// middleware.DecisionKey in the Echo context and in the request context (see middleware.FromContext).
+func Middleware(client *middleware.LicenseClient) labEcho.MiddlewareFunc {
+	guard := client.Guard()
+
+	return func(next labEcho.HandlerFunc) labEcho.HandlerFunc {
+		return func(c labEcho.Context) error {
+			decision, err := guard.CheckHTTP(c.Request())
+			if err != nil {
+				status, body := pkgHTTP.ErrorResponse(err)
+
+				return c.JSON(status, body)
+			}
+
+			if decision.OrganizationID != "" {
+				c.Set(middleware.OrganizationIDKey, decision.OrganizationID)
+				c.Set(middleware.DecisionKey, decision)
+				c.SetRequest(c.Request().WithContext(middleware.ContextWithDecision(c.Request().Context(), decision)))
+			}
+
+			for name, values := range guard.Headers(decision) {
+				c.Response().Header()[name] = values
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package echo_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/LerianStudio/lib-license-go/middleware"
+	licenseEcho "github.com/LerianStudio/lib-license-go/middleware/echo"
+	"github.com/LerianStudio/lib-license-go/test/conformance"
+	labEcho "github.com/labstack/echo/v4"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, conformance.Adapter{
+		Name: "echo",
+		Build: func(t *testing.T, client *middleware.LicenseClient, seen *conformance.Seen) func(req *http.Request) *http.Response {
+			e := labEcho.New()
+			e.GET("/", func(c labEcho.Context) error {
+				seen.Store = c.Get(middleware.OrganizationIDKey)
+				seen.Context, _ = middleware.OrganizationIDFromContext(c.Request().Context())
+				seen.StoreDecision = c.Get(middleware.DecisionKey)
+				seen.Decision, _ = middleware.FromContext(c.Request().Context())
+
+				return c.String(http.StatusOK, "success")
+			}, licenseEcho.Middleware(client))
+
+			return conformance.Record(e)
+		},
+	})
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Exemptions declares the requests the middlewares and interceptors let through without a license check,
+// and the ones that always require a license. Required rules win over skip rules.
+//
+// Path patterns use path.Match syntax ("*" matches one segment); a trailing "/**" also matches every
+// path below the prefix. gRPC patterns match full method names, e.g. "/grpc.health.v1.Health/*".
+type Exemptions struct {
+	// Paths are skipped by the HTTP middlewares
+	Paths []string
+	// Methods are HTTP methods skipped on every path, e.g. OPTIONS for CORS preflight requests
+	Methods []string
+	// GRPCMethods are skipped by the gRPC interceptors
+	GRPCMethods []string
+	// RequiredPaths always require a license, even when they match a skip rule
+	RequiredPaths []string
+	// RequiredGRPCMethods always require a license, even when they match a skip rule
+	RequiredGRPCMethods []string
+}
+
+// DefaultExemptions skips CORS preflight requests, common health and metrics endpoints,
+// and the gRPC health and reflection services. They apply until SetExemptions is called.
+func DefaultExemptions() Exemptions {
+	return Exemptions{
+		Paths:   []string{"/health/**", "/healthz", "/livez", "/readyz", "/metrics"},
+		Methods: []string{http.MethodOptions},
+		GRPCMethods: []string{
+			"/grpc.health.v1.Health/*",
+			"/grpc.reflection.v1.ServerReflection/*",
+			"/grpc.reflection.v1alpha.ServerReflection/*",
+		},
+	}
+}
+
+// SetExemptions replaces the exemption rules, including the defaults; pass Exemptions{} to check every
+// request. It returns an error for malformed patterns and must be called before serving requests.
+func (c *LicenseClient) SetExemptions(exemptions Exemptions) error {
+	if c == nil {
+		return fmt.Errorf("LicenseClient is nil, cannot set exemptions")
+	}
+
+	patterns := [][]string{exemptions.Paths, exemptions.GRPCMethods, exemptions.RequiredPaths, exemptions.RequiredGRPCMethods}
+
+	for _, list := range patterns {
+		for _, pattern := range list {
+			if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+				return fmt.Errorf("invalid exemption pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	c.exemptions = &exemptions
+
+	return nil
+}
+
+// Exempt reports whether an HTTP request skips the license check
+func (g *Guard) Exempt(method, requestPath string) bool {
+	return g.client.currentExemptions().exemptHTTP(method, requestPath)
+}
+
+// defaultExemptions holds the rules applied until SetExemptions is called
+var defaultExemptions = DefaultExemptions()
+
+// currentExemptions returns the configured exemptions, or the defaults
+func (c *LicenseClient) currentExemptions() *Exemptions {
+	if c.exemptions == nil {
+		return &defaultExemptions
+	}
+
+	return c.exemptions
+}
+
+// exemptHTTP reports whether an HTTP request skips the license check
+func (e *Exemptions) exemptHTTP(method, requestPath string) bool {
+	if matchAny(e.RequiredPaths, requestPath) {
+		return false
+	}
+
+	for _, m := range e.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+
+	return matchAny(e.Paths, requestPath)
+}
+
+// exemptGRPC reports whether a gRPC method skips the license check
+func (e *Exemptions) exemptGRPC(fullMethod string) bool {
+	if matchAny(e.RequiredGRPCMethods, fullMethod) {
+		return false
+	}
+
+	return matchAny(e.GRPCMethods, fullMethod)
+}
+
+// matchAny reports whether value matches any of the patterns
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchPattern matches value against a path.Match pattern, where a trailing "/**" matches any depth
+func matchPattern(pattern, value string) bool {
+	prefix, recursive := strings.CutSuffix(pattern, "/**")
+	if !recursive {
+		ok, _ := path.Match(pattern, value)
+		return ok
+	}
+
+	// Compare the prefix with as many leading segments of the value
+	segments := strings.Count(prefix, "/")
+
+	candidate := value
+	for i, n := 0, 0; i < len(value); i++ {
+		if value[i] == '/' {
+			if n == segments {
+				candidate = value[:i]
+				break
+			}
+
+			n++
+		}
+	}
+
+	ok, _ := path.Match(prefix, candidate)
+
+	return ok
+}
@@ -0,0 +1,38 @@
+// Package gin adapts the license middleware to the Gin framework
+package gin
+
+import (
+	"github.com/LerianStudio/lib-license-go/middleware"
+	pkgHTTP "github.com/LerianStudio/lib-license-go/pkg/net/http"
+	ginGonic "github.com/gin-gonic/gin"
+)
+
+// Middleware creates a Gin middleware that validates the license like the Fiber middleware.
+// The organization ID is resolved with the client's HTTPOrgResolver (the X-Organization-ID header by
+// default). It and the license decision are stored under middleware.OrganizationIDKey and
+// middleware.DecisionKey in the Gin context and in the request context (see middleware.FromContext).
+func Middleware(client *middleware.LicenseClient) ginGonic.HandlerFunc {
+	guard := client.Guard()
+
+	return func(c *ginGonic.Context) {
+		decision, err := guard.CheckHTTP(c.Request)
+		if err != nil {
+			status, body := pkgHTTP.ErrorResponse(err)
+			c.AbortWithStatusJSON(status, body)
+
+			return
+		}
+
+		if decision.OrganizationID != "" {
+			c.Set(middleware.OrganizationIDKey, decision.OrganizationID)
+			c.Set(middleware.DecisionKey, decision)
+			c.Request = c.Request.WithContext(middleware.ContextWithDecision(c.Request.Context(), decision))
+		}
+
+		for name, values := range guard.Headers(decision) {
+			c.Header(name, values[0])
+		}
+
+		c.Next()
+	}
+}
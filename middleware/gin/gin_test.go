@@ -0,0 +1,31 @@
+package gin_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/LerianStudio/lib-license-go/middleware"
+	licenseGin "github.com/LerianStudio/lib-license-go/middleware/gin"
+	"github.com/LerianStudio/lib-license-go/test/conformance"
+	ginGonic "github.com/gin-gonic/gin"
+)
+
+func TestConformance(t *testing.T) {
+	ginGonic.SetMode(ginGonic.TestMode)
+
+	conformance.Run(t, conformance.Adapter{
+		Name: "gin",
+		Build: func(t *testing.T, client *middleware.LicenseClient, seen *conformance.Seen) func(req *http.Request) *http.Response {
+			engine := ginGonic.New()
+			engine.GET("/", licenseGin.Middleware(client), func(c *ginGonic.Context) {
+				seen.Store, _ = c.Get(middleware.OrganizationIDKey)
+				seen.Context, _ = middleware.OrganizationIDFromContext(c.Request.Context())
+				seen.StoreDecision, _ = c.Get(middleware.DecisionKey)
+				seen.Decision, _ = middleware.FromContext(c.Request.Context())
+				c.String(http.StatusOK, "success")
+			})
+
+			return conformance.Record(engine)
+		},
+	})
+}
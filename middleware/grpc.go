@@ -4,10 +4,11 @@ import (
 	"context"
 
 	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/telemetry"
 	"github.com/LerianStudio/lib-license-go/pkg"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -30,8 +31,20 @@ func (c *LicenseClient) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		// Validate client initialization for each request
 		c.ValidateInitialization("process unary request")
 
+		// Health checks, reflection and other exempt methods skip the license check
+		if c.currentExemptions().exemptGRPC(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		// Reject every call when the application started without a valid license
+		if err := c.grpcStartupFailure(); err != nil {
+			return nil, err
+		}
+
 		if c.validator.IsGlobal {
 			// In global mode, validation happens at startup and through background refresh
+			c.setUnaryLicenseHeader(ctx, Decision{global: true})
+
 			return handler(ctx, req)
 		}
 
@@ -57,42 +70,82 @@ func (c *LicenseClient) StreamServerInterceptor() grpc.StreamServerInterceptor {
 		// Validate client initialization for each request
 		c.ValidateInitialization("process stream request")
 
+		// Health checks, reflection and other exempt methods skip the license check
+		if c.currentExemptions().exemptGRPC(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		// Reject every call when the application started without a valid license
+		if err := c.grpcStartupFailure(); err != nil {
+			return err
+		}
+
 		if c.validator.IsGlobal {
 			// In global mode, validation happens at startup and through background refresh
+			c.setStreamLicenseHeader(ss, Decision{global: true})
+
 			return handler(srv, ss)
 		}
 
-		// Validate organization ID from gRPC metadata
-		if err := c.validateGRPCOrganizationID(ss.Context()); err != nil {
+		// Validate the organization ID of the call
+		decision, err := c.openStream(ss.Context())
+		if err != nil {
 			return err
 		}
 
-		// Continue with the stream handling
-		return handler(srv, ss)
+		c.setStreamLicenseHeader(ss, decision)
+
+		// Continue with the stream handling, re-checking the license while the stream lives
+		return c.serveStream(srv, ss, decision, handler)
 	}
 }
 
-// validateGRPCOrganizationID extracts and validates the organization ID from gRPC metadata
-// Returns an error if validation fails
+// grpcStartupFailure returns a PermissionDenied status when startup license validation failed
+func (c *LicenseClient) grpcStartupFailure() error {
+	code := c.startupFailure()
+	if code == nil {
+		return nil
+	}
+
+	c.validator.GetLogger().Errorf("Rejecting call: startup license validation failed (code %s)", code.Error())
+
+	return status.Error(codes.PermissionDenied, code.Error())
+}
+
+// validateGRPCOrganizationID extracts and validates the organization ID of a gRPC call
+// Returns the license decision, or an error if validation fails
 // This is a helper function to avoid code duplication between unary and stream interceptors
-func (c *LicenseClient) validateGRPCOrganizationID(ctx context.Context) error {
+func (c *LicenseClient) validateGRPCOrganizationID(ctx context.Context, req any) (Decision, error) {
 	l := c.validator.GetLogger()
 
-	// Extract organization ID from metadata
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		l.Error("Failed to extract metadata from gRPC context")
-		return status.Error(codes.Internal, "missing metadata")
+	// Extract organization ID with the configured resolver
+	orgID, err := c.resolveGRPCOrgID(ctx, req)
+	if err != nil {
+		l.Errorf("Failed to resolve org ID: %v", err)
+		return Decision{}, grpcResolveError(err)
 	}
 
-	// Get organization ID from metadata
-	orgIDs := md.Get(cn.OrganizationIDHeader)
-	if len(orgIDs) == 0 {
+	if orgID == "" {
 		l.Errorf("Missing org header (code %s)", cn.ErrMissingOrgIDHeader.Error())
-		return status.Error(codes.InvalidArgument, cn.ErrMissingOrgIDHeader.Error())
+		return Decision{}, status.Error(codes.InvalidArgument, cn.ErrMissingOrgIDHeader.Error())
 	}
 
-	orgID := orgIDs[0]
+	return c.checkGRPCOrganization(ctx, orgID)
+}
+
+// checkGRPCOrganization validates the license of a resolved organization ID
+func (c *LicenseClient) checkGRPCOrganization(ctx context.Context, orgID string) (Decision, error) {
+	ctx, span := c.validator.Tracer().Start(ctx, "license.check", trace.WithAttributes(telemetry.OrganizationIDKey.String(orgID)))
+
+	decision, err := c.checkGRPCLicense(ctx, orgID)
+	endCheckSpan(span, decision, err)
+
+	return decision, err
+}
+
+// checkGRPCLicense validates the license of an organization within the span of checkGRPCOrganization
+func (c *LicenseClient) checkGRPCLicense(ctx context.Context, orgID string) (Decision, error) {
+	l := c.validator.GetLogger()
 
 	// Validate the organization ID
 	res, err := c.validateOrganizationID(ctx, orgID)
@@ -100,22 +153,22 @@ func (c *LicenseClient) validateGRPCOrganizationID(ctx context.Context) error {
 		if err == cn.ErrUnknownOrgIDHeader {
 			l.Errorf("Unknown org ID %s", orgID)
 
-			return status.Error(codes.InvalidArgument, cn.ErrUnknownOrgIDHeader.Error())
+			return Decision{}, status.Error(codes.InvalidArgument, cn.ErrUnknownOrgIDHeader.Error())
 		}
 
 		l.Errorf("Validation failed for org %s: %v", orgID, err)
 
-		return status.Error(codes.PermissionDenied, pkg.ValidateBusinessError(err, "", orgID).Error())
+		return Decision{}, status.Error(codes.PermissionDenied, pkg.ValidateBusinessError(err, "", orgID).Error())
 	}
 
 	// Check if the license is valid
 	if !res.Valid && !res.ActiveGracePeriod {
 		l.Errorf("Org %s license invalid", orgID)
 
-		return status.Error(codes.PermissionDenied, cn.ErrOrgLicenseInvalid.Error())
+		return Decision{}, status.Error(codes.PermissionDenied, cn.ErrOrgLicenseInvalid.Error())
 	}
 
-	return nil
+	return Decision{OrganizationID: orgID, Result: res}, nil
 }
 
 // processGRPCMultiOrgRequest handles gRPC requests in multi-org mode
@@ -125,11 +178,14 @@ func (c *LicenseClient) processGRPCMultiOrgRequest(
 	_ *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (any, error) {
-	// Validate organization ID from gRPC metadata
-	if err := c.validateGRPCOrganizationID(ctx); err != nil {
+	// Validate the organization ID of the call
+	decision, err := c.validateGRPCOrganizationID(ctx, req)
+	if err != nil {
 		return nil, err
 	}
 
-	// Continue with the request handling
-	return handler(ctx, req)
+	c.setUnaryLicenseHeader(ctx, decision)
+
+	// Continue with the request handling, exposing the decision to the handler
+	return handler(ContextWithDecision(ctx, decision), req)
 }
@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"sync"
+	"time"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultClientTokenTTL is the lifetime of the license-context tokens signed by the client interceptors
+const defaultClientTokenTTL = 5 * time.Minute
+
+// WithOrganizationID returns a copy of ctx whose outgoing gRPC calls carry the organization ID when
+// made through UnaryClientInterceptor or StreamClientInterceptor
+func WithOrganizationID(ctx context.Context, orgID string) context.Context {
+	return ContextWithOrganizationID(ctx, orgID)
+}
+
+// ClientTokenConfig configures the signed license-context token attached to outgoing calls.
+// The receiving service verifies it with a JWTResolver trusting the matching public key.
+type ClientTokenConfig struct {
+	// Key signs the tokens: *rsa.PrivateKey (RS256), P-256 *ecdsa.PrivateKey (ES256) or ed25519.PrivateKey (EdDSA)
+	Key crypto.Signer
+	// KeyID is sent as the kid header so the receiver can pick the key from its JWKS
+	KeyID string
+	// Claim is the token claim holding the organization ID (default "organization_id")
+	Claim string
+	// Issuer and Audience, when set, are sent as the iss and aud claims
+	Issuer   string
+	Audience string
+	// TTL is the lifetime of each token (default 5 minutes). Tokens are reused until half of it has elapsed.
+	TTL time.Duration
+}
+
+// ClientInterceptorOption customizes the gRPC client interceptors
+type ClientInterceptorOption func(*clientInterceptorConfig)
+
+// clientInterceptorConfig holds the settings of the gRPC client interceptors
+type clientInterceptorConfig struct {
+	tokens *clientTokenSource
+}
+
+// WithClientToken attaches a signed license-context token to every outgoing call whose organization ID
+// was set with WithOrganizationID or accepted by the license middleware, as "authorization: Bearer <token>",
+// unless the call already carries authorization metadata. It returns an error when Key cannot sign tokens.
+func WithClientToken(cfg ClientTokenConfig) (ClientInterceptorOption, error) {
+	if _, err := jwt.SigningAlgorithm(cfg.Key); err != nil {
+		return nil, fmt.Errorf("invalid client token key: %w", err)
+	}
+
+	if cfg.Claim == "" {
+		cfg.Claim = defaultOrgClaim
+	}
+
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultClientTokenTTL
+	}
+
+	return func(c *clientInterceptorConfig) {
+		c.tokens = &clientTokenSource{cfg: cfg, tokens: make(map[string]clientToken), now: time.Now}
+	}, nil
+}
+
+// UnaryClientInterceptor creates a gRPC unary client interceptor that attaches the organization ID to
+// outgoing calls. The organization ID comes from the outgoing X-Organization-ID metadata when already set,
+// then from WithOrganizationID (or an HTTP middleware) on the context, then from the incoming metadata of
+// the call being served, so service-to-service calls keep the caller's organization. That incoming metadata is
+// not verified, so WithClientToken signs no token for it.
+func UnaryClientInterceptor(opts ...ClientInterceptorOption) grpc.UnaryClientInterceptor {
+	cfg := newClientInterceptorConfig(opts)
+
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		callOpts ...grpc.CallOption,
+	) error {
+		ctx, err := cfg.outgoingContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, callOpts...)
+	}
+}
+
+// StreamClientInterceptor creates a gRPC stream client interceptor that attaches the organization ID to
+// outgoing streams, like UnaryClientInterceptor
+func StreamClientInterceptor(opts ...ClientInterceptorOption) grpc.StreamClientInterceptor {
+	cfg := newClientInterceptorConfig(opts)
+
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		callOpts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		ctx, err := cfg.outgoingContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return streamer(ctx, desc, cc, method, callOpts...)
+	}
+}
+
+// newClientInterceptorConfig applies the options
+func newClientInterceptorConfig(opts []ClientInterceptorOption) *clientInterceptorConfig {
+	cfg := &clientInterceptorConfig{}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+
+	return cfg
+}
+
+// outgoingContext adds the organization ID, and the license-context token when configured, to the
+// outgoing metadata
+func (c *clientInterceptorConfig) outgoingContext(ctx context.Context) (context.Context, error) {
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+
+	// Set by the application or accepted by the license middleware for the call being served
+	trusted, _ := OrganizationIDFromContext(ctx)
+
+	orgID := firstValue(outgoing, cn.OrganizationIDHeader)
+	if orgID == "" {
+		orgID = trusted
+		if orgID == "" {
+			incoming, _ := metadata.FromIncomingContext(ctx)
+			orgID = firstValue(incoming, cn.OrganizationIDHeader)
+		}
+
+		if orgID == "" {
+			return ctx, nil
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, cn.OrganizationIDHeader, orgID)
+	}
+
+	// Never vouch for an organization ID that was only forwarded from the caller
+	if c.tokens == nil || orgID != trusted || len(outgoing.Get("authorization")) > 0 {
+		return ctx, nil
+	}
+
+	token, err := c.tokens.token(orgID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign license context token: %v", err)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token), nil
+}
+
+// clientToken is a signed token and the time after which it is renewed
+type clientToken struct {
+	value   string
+	renewAt time.Time
+}
+
+// clientTokenSource signs license-context tokens and reuses them per organization
+type clientTokenSource struct {
+	cfg ClientTokenConfig
+	now func() time.Time
+
+	mu     sync.Mutex
+	tokens map[string]clientToken
+}
+
+// token returns a token for the organization, signing a new one when none is fresh
+func (s *clientTokenSource) token(orgID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if cached, ok := s.tokens[orgID]; ok && now.Before(cached.renewAt) {
+		return cached.value, nil
+	}
+
+	claims := jwt.Claims{
+		s.cfg.Claim: orgID,
+		"iat":       now.Unix(),
+		"exp":       now.Add(s.cfg.TTL).Unix(),
+	}
+
+	if s.cfg.Issuer != "" {
+		claims["iss"] = s.cfg.Issuer
+	}
+
+	if s.cfg.Audience != "" {
+		claims["aud"] = s.cfg.Audience
+	}
+
+	value, err := jwt.Sign(claims, s.cfg.KeyID, s.cfg.Key)
+	if err != nil {
+		return "", err
+	}
+
+	s.tokens[orgID] = clientToken{value: value, renewAt: now.Add(s.cfg.TTL / 2)}
+
+	return value, nil
+}
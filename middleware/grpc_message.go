@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OrganizationIDGetter is implemented by request messages carrying the organization ID,
+// such as protobuf messages with an organization_id field
+type OrganizationIDGetter interface {
+	GetOrganizationId() string
+}
+
+// messageResolver reads the organization ID from request messages
+type messageResolver struct {
+	path []string
+}
+
+// MessageResolver reads the organization ID from gRPC request messages. With an empty field path it
+// uses the GetOrganizationId() getter; otherwise it follows the dotted protobuf field path (e.g.
+// "tenant.organization_id"), falling back to the getter for messages that are not protobuf messages.
+// When a resolver includes a MessageResolver, the stream interceptor validates the organization ID
+// of every message received on client and bidirectional streams.
+func MessageResolver(fieldPath string) GRPCOrgResolver {
+	var path []string
+	if fieldPath != "" {
+		path = strings.Split(fieldPath, ".")
+	}
+
+	return &messageResolver{path: path}
+}
+
+// ResolveGRPC reads the organization ID from req, returning an empty string for a nil request
+func (r *messageResolver) ResolveGRPC(_ context.Context, req any) (string, error) {
+	if req == nil {
+		return "", nil
+	}
+
+	if msg, ok := req.(proto.Message); ok && len(r.path) > 0 {
+		return protoStringField(msg.ProtoReflect(), r.path)
+	}
+
+	if getter, ok := req.(OrganizationIDGetter); ok {
+		return getter.GetOrganizationId(), nil
+	}
+
+	return "", nil
+}
+
+// readsMessages marks the resolver as reading request messages
+func (r *messageResolver) readsMessages() bool {
+	return true
+}
+
+// protoStringField returns the string field at path, or an empty string when an intermediate message is unset
+func protoStringField(msg protoreflect.Message, path []string) (string, error) {
+	for i, name := range path {
+		fields := msg.Descriptor().Fields()
+
+		field := fields.ByName(protoreflect.Name(name))
+		if field == nil {
+			field = fields.ByJSONName(name)
+		}
+
+		if field == nil || field.IsList() || field.IsMap() {
+			return "", fmt.Errorf("message %s has no field %q", msg.Descriptor().FullName(), name)
+		}
+
+		if i == len(path)-1 {
+			if field.Kind() != protoreflect.StringKind {
+				return "", fmt.Errorf("field %s is not a string", field.FullName())
+			}
+
+			return msg.Get(field).String(), nil
+		}
+
+		if field.Message() == nil {
+			return "", fmt.Errorf("field %s is not a message", field.FullName())
+		}
+
+		if !msg.Has(field) {
+			return "", nil
+		}
+
+		msg = msg.Get(field).Message()
+	}
+
+	return "", nil
+}
+
+// grpcResolverChain combines gRPC resolvers in priority order
+type grpcResolverChain []GRPCOrgResolver
+
+// ChainGRPCResolvers combines gRPC resolvers in priority order, e.g. the request message before the
+// metadata. The first organization ID found is used, and the call is rejected with an
+// *OrgIDConflictError when another source carries a different one.
+func ChainGRPCResolvers(resolvers ...GRPCOrgResolver) GRPCOrgResolver {
+	return grpcResolverChain(resolvers)
+}
+
+// ResolveGRPC returns the first organization ID found, rejecting conflicting sources
+func (c grpcResolverChain) ResolveGRPC(ctx context.Context, req any) (string, error) {
+	var orgID string
+
+	for _, resolver := range c {
+		value, err := resolver.ResolveGRPC(ctx, req)
+		if err != nil {
+			return "", err
+		}
+
+		if value == "" {
+			continue
+		}
+
+		if orgID == "" {
+			orgID = value
+			continue
+		}
+
+		if value != orgID {
+			return "", &OrgIDConflictError{First: orgID, Second: value}
+		}
+	}
+
+	return orgID, nil
+}
+
+// readsMessages reports whether any resolver of the chain reads request messages
+func (c grpcResolverChain) readsMessages() bool {
+	for _, resolver := range c {
+		if readsMessages(resolver) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readsMessages reports whether a resolver reads request messages
+func readsMessages(resolver GRPCOrgResolver) bool {
+	r, ok := resolver.(interface{ readsMessages() bool })
+
+	return ok && r.readsMessages()
+}
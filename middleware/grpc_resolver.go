@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errMissingMetadata is returned when an incoming gRPC context carries no metadata
+var errMissingMetadata = errors.New("missing metadata")
+
+// GRPCOrgResolver extracts the organization ID from an incoming gRPC call.
+// req is the request message of unary calls and nil for streams.
+// It returns an empty string when its source does not carry an organization ID.
+type GRPCOrgResolver interface {
+	ResolveGRPC(ctx context.Context, req any) (string, error)
+}
+
+// GRPCOrgResolverFunc adapts a function to the GRPCOrgResolver interface
+type GRPCOrgResolverFunc func(ctx context.Context, req any) (string, error)
+
+// ResolveGRPC calls f(ctx, req)
+func (f GRPCOrgResolverFunc) ResolveGRPC(ctx context.Context, req any) (string, error) {
+	return f(ctx, req)
+}
+
+// MetadataResolver reads the organization ID from incoming gRPC metadata
+func MetadataResolver(key string) GRPCOrgResolver {
+	return GRPCOrgResolverFunc(func(ctx context.Context, _ any) (string, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return "", errMissingMetadata
+		}
+
+		return firstValue(md, key), nil
+	})
+}
+
+// SetGRPCOrgResolver changes how the gRPC interceptors extract the organization ID from calls.
+// The default reads the X-Organization-ID metadata. It must be called before serving calls.
+func (c *LicenseClient) SetGRPCOrgResolver(resolver GRPCOrgResolver) {
+	if c != nil {
+		c.grpcOrgResolver = resolver
+	}
+}
+
+// resolveGRPCOrgID extracts the organization ID of the call with the configured resolver
+func (c *LicenseClient) resolveGRPCOrgID(ctx context.Context, req any) (string, error) {
+	if c.grpcOrgResolver == nil {
+		return MetadataResolver(cn.OrganizationIDHeader).ResolveGRPC(ctx, req)
+	}
+
+	return c.grpcOrgResolver.ResolveGRPC(ctx, req)
+}
+
+// grpcResolveError converts an organization resolution error into a gRPC status
+func grpcResolveError(err error) error {
+	var (
+		conflictErr *OrgIDConflictError
+		mismatchErr *OrgClaimMismatchError
+	)
+
+	switch {
+	case errors.As(err, &conflictErr):
+		return status.Error(codes.InvalidArgument, cn.ErrConflictingOrgID.Error())
+	case errors.As(err, &mismatchErr):
+		return status.Error(codes.PermissionDenied, cn.ErrOrgClaimMismatch.Error())
+	case errors.Is(err, cn.ErrInvalidOrgToken):
+		return status.Error(codes.Unauthenticated, cn.ErrInvalidOrgToken.Error())
+	case errors.Is(err, errMissingMetadata):
+		return status.Error(codes.Internal, errMissingMetadata.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,316 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetStreamRevalidation makes StreamServerInterceptor re-check the organization's license while a stream
+// is open: every interval (when positive) and after every messages sent or received (when positive).
+// A stream whose license becomes invalid is terminated with codes.PermissionDenied and the LCS code,
+// even while its handler waits in RecvMsg. Revalidation reads the cached result, so a revocation is seen
+// at the latest once the cached result expires, up to the cache TTL after the last validation; a refresh or validation that finds the
+// license invalid or revoked evicts it earlier. It is disabled by default and must be configured before
+// serving calls.
+func (c *LicenseClient) SetStreamRevalidation(interval time.Duration, messages int) {
+	if c != nil {
+		c.streamRevalidateInterval = interval
+		c.streamRevalidateMessages = messages
+	}
+}
+
+// openStream validates the organization of a new stream. When the resolver reads request messages the
+// organization ID may only arrive with the first message, so a missing ID is accepted here.
+func (c *LicenseClient) openStream(ctx context.Context) (Decision, error) {
+	if !readsMessages(c.grpcOrgResolver) {
+		return c.validateGRPCOrganizationID(ctx, nil)
+	}
+
+	orgID, err := c.resolveGRPCOrgID(ctx, nil)
+	if err != nil {
+		c.validator.GetLogger().Errorf("Failed to resolve org ID: %v", err)
+		return Decision{}, grpcResolveError(err)
+	}
+
+	if orgID == "" {
+		return Decision{}, nil
+	}
+
+	return c.checkGRPCOrganization(ctx, orgID)
+}
+
+// serveStream runs the stream handler, wrapping the stream when it must be revalidated or when the
+// organization ID of received messages must be validated. The stream context carries the decision.
+func (c *LicenseClient) serveStream(srv any, ss grpc.ServerStream, decision Decision, handler grpc.StreamHandler) error {
+	validateMessages := readsMessages(c.grpcOrgResolver)
+
+	if c.streamRevalidateInterval <= 0 && c.streamRevalidateMessages <= 0 && !validateMessages {
+		return handler(srv, &decisionStream{ServerStream: ss, ctx: ContextWithDecision(ss.Context(), decision)})
+	}
+
+	ctx, cancel := context.WithCancel(ss.Context())
+	defer cancel()
+
+	stream := &licensedStream{
+		ServerStream:     ss,
+		client:           c,
+		decision:         decision,
+		ctx:              ctx,
+		cancel:           cancel,
+		failed:           make(chan struct{}),
+		every:            int64(c.streamRevalidateMessages),
+		validateMessages: validateMessages,
+	}
+
+	if c.streamRevalidateInterval > 0 {
+		done := make(chan struct{})
+		defer close(done)
+
+		go stream.watch(c.streamRevalidateInterval, done)
+	}
+
+	// A handler waiting in RecvMsg on an idle stream does not see the cancellation, so a failed license
+	// ends the call without waiting for it. Returning closes the transport stream, which unblocks RecvMsg.
+	result := make(chan streamResult, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- streamResult{panicked: true, value: r}
+			}
+		}()
+
+		result <- streamResult{err: handler(srv, stream)}
+	}()
+
+	select {
+	case res := <-result:
+		if res.panicked {
+			panic(res.value)
+		}
+
+		// The handler usually reports the cancellation; the license failure is the real cause
+		if failure := stream.failure(); failure != nil {
+			return failure
+		}
+
+		return res.err
+	case <-stream.failed:
+		return stream.failure()
+	}
+}
+
+// streamResult is the outcome of a stream handler run by serveStream
+type streamResult struct {
+	err      error
+	panicked bool
+	value    any
+}
+
+// decisionStream is a server stream whose context carries the license decision
+type decisionStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream context with the decision
+func (s *decisionStream) Context() context.Context {
+	return s.ctx
+}
+
+// licensedStream is a server stream that re-checks the organization's license while it lives and
+// validates the organization ID carried by received messages
+type licensedStream struct {
+	grpc.ServerStream
+	client           *LicenseClient
+	ctx              context.Context
+	cancel           context.CancelFunc
+	failed           chan struct{}
+	every            int64
+	validateMessages bool
+
+	messages atomic.Int64
+
+	mu       sync.Mutex
+	decision Decision
+	err      error
+}
+
+// Context returns the stream context, canceled when the license becomes invalid. It carries the
+// decision of the last validation, which is empty until a message brings the organization ID.
+func (s *licensedStream) Context() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.decision.OrganizationID == "" {
+		return s.ctx
+	}
+
+	return ContextWithDecision(s.ctx, s.decision)
+}
+
+// SendMsg sends a message unless the license became invalid. When the organization ID comes from
+// received messages nothing is sent before a message has brought it and its license was validated.
+func (s *licensedStream) SendMsg(m any) error {
+	if s.validateMessages && s.currentOrgID() == "" {
+		if err := s.failure(); err != nil {
+			return err
+		}
+
+		s.client.validator.GetLogger().Errorf("Rejecting stream send before the org ID is known (code %s)", cn.ErrMissingOrgIDHeader.Error())
+
+		return status.Error(codes.FailedPrecondition, cn.ErrMissingOrgIDHeader.Error())
+	}
+
+	if err := s.countMessage(); err != nil {
+		return err
+	}
+
+	return s.ServerStream.SendMsg(m)
+}
+
+// RecvMsg receives a message unless the license became invalid
+func (s *licensedStream) RecvMsg(m any) error {
+	if err := s.failure(); err != nil {
+		return err
+	}
+
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if s.validateMessages {
+		if err := s.validateMessage(m); err != nil {
+			return err
+		}
+	}
+
+	return s.countMessage()
+}
+
+// validateMessage validates the organization ID of a received message. Messages without one
+// belong to the organization the stream already uses.
+func (s *licensedStream) validateMessage(m any) error {
+	c := s.client
+
+	orgID, err := c.resolveGRPCOrgID(s.ServerStream.Context(), m)
+	if err != nil {
+		c.validator.GetLogger().Errorf("Failed to resolve org ID: %v", err)
+		return s.fail(grpcResolveError(err))
+	}
+
+	if orgID == "" {
+		if s.currentOrgID() != "" {
+			return nil
+		}
+
+		c.validator.GetLogger().Errorf("Missing org ID in stream message (code %s)", cn.ErrMissingOrgIDHeader.Error())
+
+		return s.fail(status.Error(codes.InvalidArgument, cn.ErrMissingOrgIDHeader.Error()))
+	}
+
+	decision, err := c.checkGRPCOrganization(s.ServerStream.Context(), orgID)
+	if err != nil {
+		return s.fail(err)
+	}
+
+	s.setDecision(decision)
+
+	return nil
+}
+
+// countMessage revalidates the license every s.every messages
+func (s *licensedStream) countMessage() error {
+	if err := s.failure(); err != nil {
+		return err
+	}
+
+	if s.every > 0 && s.messages.Add(1)%s.every == 0 {
+		return s.revalidate()
+	}
+
+	return nil
+}
+
+// watch revalidates the license every interval until done is closed or the stream ends
+func (s *licensedStream) watch(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if s.revalidate() != nil {
+				return
+			}
+		}
+	}
+}
+
+// revalidate re-checks the license, terminating the stream when it is no longer valid
+func (s *licensedStream) revalidate() error {
+	orgID := s.currentOrgID()
+	if orgID == "" {
+		// No message has carried the organization ID yet
+		return nil
+	}
+
+	decision, err := s.client.checkGRPCOrganization(s.ServerStream.Context(), orgID)
+	if err != nil {
+		s.client.validator.GetLogger().Errorf("Terminating stream of org %s: license is no longer valid", orgID)
+
+		return s.fail(err)
+	}
+
+	s.setDecision(decision)
+
+	return nil
+}
+
+// fail terminates the stream with err, keeping the first failure
+func (s *licensedStream) fail(err error) error {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+		close(s.failed)
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+
+	return s.failure()
+}
+
+// currentOrgID returns the organization the stream is validated for
+func (s *licensedStream) currentOrgID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.decision.OrganizationID
+}
+
+// setDecision records the decision of the last successful validation
+func (s *licensedStream) setDecision(decision Decision) {
+	s.mu.Lock()
+	s.decision = decision
+	s.mu.Unlock()
+}
+
+// failure returns the error that terminated the stream, if any
+func (s *licensedStream) failure() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/telemetry"
+	"github.com/LerianStudio/lib-license-go/pkg"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Guard is the framework-neutral core of the HTTP middlewares and of the framework adapters in
+// middleware/gin and middleware/echo. It resolves the organization ID, validates its license and maps
+// failures to the LCS business errors rendered by pkg/net/http, so every framework behaves the same.
+type Guard struct {
+	client *LicenseClient
+}
+
+// Guard performs the startup validation shared by every middleware and returns the request guard.
+// Like Middleware(), it panics when the client is nil or misconfigured.
+func (c *LicenseClient) Guard() *Guard {
+	// Validate client initialization
+	c.ValidateInitialization("create middleware")
+
+	// Perform startup validation
+	c.startupValidation()
+
+	return &Guard{client: c}
+}
+
+// Check applies the license checks to a request. resolve extracts the organization ID and is only
+// called in multi-organization mode. It returns the decision of an accepted request (without organization
+// in global mode), or the business error to render when the request is rejected.
+func (g *Guard) Check(ctx context.Context, resolve func() (string, error)) (Decision, error) {
+	ctx, span := g.client.validator.Tracer().Start(ctx, "license.check")
+
+	decision, err := g.check(ctx, resolve)
+	endCheckSpan(span, decision, err)
+
+	return decision, err
+}
+
+// check applies the license checks of Check within its span
+func (g *Guard) check(ctx context.Context, resolve func() (string, error)) (Decision, error) {
+	c := g.client
+
+	// Validate client initialization for each request
+	c.ValidateInitialization("process request")
+
+	l := c.validator.GetLogger()
+
+	// Reject every request when the application started without a valid license
+	if code := c.startupFailure(); code != nil {
+		l.Errorf("Rejecting request: startup license validation failed (code %s)", code.Error())
+		return Decision{}, pkg.ValidateBusinessError(code, "")
+	}
+
+	// In global mode, validation happens at startup and through background refresh
+	if c.validator.IsGlobal {
+		return Decision{global: true}, nil
+	}
+
+	orgID, err := resolve()
+	if err != nil {
+		return Decision{}, c.resolveError(err)
+	}
+
+	// Use the shared validation function
+	res, err := c.validateOrganizationID(ctx, orgID)
+	if err != nil {
+		if err == cn.ErrMissingOrgIDHeader {
+			l.Errorf("Missing org header (code %s)", cn.ErrMissingOrgIDHeader.Error())
+			return Decision{}, pkg.ValidateBusinessError(err, "", cn.OrganizationIDHeader)
+		}
+
+		if err == cn.ErrUnknownOrgIDHeader {
+			l.Errorf("Unknown org ID %s", orgID)
+			return Decision{}, pkg.ValidateBusinessError(err, "", orgID)
+		}
+
+		l.Errorf("Validation failed for org %s: %v", orgID, err)
+
+		return Decision{}, pkg.ValidateBusinessError(err, "", orgID)
+	}
+
+	// Check if license is valid
+	if !res.Valid && !res.ActiveGracePeriod {
+		l.Errorf("Org %s license invalid", orgID)
+
+		return Decision{}, pkg.ValidateBusinessError(cn.ErrOrgLicenseInvalid, "", orgID)
+	}
+
+	return Decision{OrganizationID: orgID, Result: res}, nil
+}
+
+// CheckHTTP applies the license checks to a net/http request, resolving the organization ID with the
+// resolver set by SetHTTPOrgResolver. Exempt requests are accepted with an empty decision.
+func (g *Guard) CheckHTTP(r *http.Request) (Decision, error) {
+	if g.Exempt(r.Method, r.URL.Path) {
+		return Decision{}, nil
+	}
+
+	return g.Check(r.Context(), func() (string, error) {
+		return g.client.resolveHTTPOrgID(r)
+	})
+}
+
+// endCheckSpan records the organization and the outcome of a license check on its span and ends it
+func endCheckSpan(span trace.Span, decision Decision, err error) {
+	if decision.OrganizationID != "" {
+		span.SetAttributes(telemetry.OrganizationIDKey.String(decision.OrganizationID))
+	}
+
+	switch {
+	case err != nil:
+		telemetry.EndSpan(span, telemetry.OutcomeRejected, err)
+	case decision.Result.ActiveGracePeriod:
+		telemetry.EndSpan(span, telemetry.OutcomeGrace, nil)
+	default:
+		telemetry.EndSpan(span, telemetry.OutcomeValid, nil)
+	}
+}
+
+// resolveError converts an organization resolution error into the business error to render
+func (c *LicenseClient) resolveError(err error) error {
+	l := c.validator.GetLogger()
+
+	var conflictErr *OrgIDConflictError
+	if errors.As(err, &conflictErr) {
+		l.Errorf("Conflicting org IDs %s and %s (code %s)", conflictErr.First, conflictErr.Second, cn.ErrConflictingOrgID.Error())
+		return pkg.ValidateBusinessError(cn.ErrConflictingOrgID, "", conflictErr.First, conflictErr.Second)
+	}
+
+	var mismatchErr *OrgClaimMismatchError
+	if errors.As(err, &mismatchErr) {
+		l.Errorf("Token org %s does not match header org %s (code %s)", mismatchErr.Claim, mismatchErr.Header, cn.ErrOrgClaimMismatch.Error())
+		return pkg.ValidateBusinessError(cn.ErrOrgClaimMismatch, "", mismatchErr.Claim, mismatchErr.Header)
+	}
+
+	if errors.Is(err, cn.ErrInvalidOrgToken) {
+		l.Errorf("Rejected org token: %v", err)
+		return pkg.ValidateBusinessError(cn.ErrInvalidOrgToken, "")
+	}
+
+	l.Errorf("Failed to resolve org ID: %v", err)
+
+	return err
+}
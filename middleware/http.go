@@ -1,73 +1,40 @@
 package middleware
 
 import (
-	cn "github.com/LerianStudio/lib-license-go/constant"
-	"github.com/LerianStudio/lib-license-go/pkg"
 	pkgHTTP "github.com/LerianStudio/lib-license-go/pkg/net/http"
 	"github.com/gofiber/fiber/v2"
 )
 
 // Middleware creates a Fiber middleware that validates the license and manages background refresh
 func (c *LicenseClient) Middleware() fiber.Handler {
-	// Validate client initialization
-	c.ValidateInitialization("create middleware")
-
-	// Perform startup validation
-	c.startupValidation()
+	guard := c.Guard()
 
 	// Return request handler
 	return func(ctx *fiber.Ctx) error {
-		// Validate client initialization for each request
-		c.ValidateInitialization("process request")
-
-		if c.validator.IsGlobal {
-			return c.processGlobalPluginRequest(ctx)
+		// Health checks, preflight requests and other exempt routes skip the license check
+		if guard.Exempt(ctx.Method(), ctx.Path()) {
+			return ctx.Next()
 		}
 
-		return c.processMultiOrgPluginRequest(ctx)
-	}
-}
-
-// processGlobalPluginRequest handles requests in global plugin mode.
-// Since validation happens at startup and through background refresh,
-// we don't need to validate on each request for global mode.
-func (c *LicenseClient) processGlobalPluginRequest(ctx *fiber.Ctx) error {
-	// In global mode, we're already validated at startup and through background refresh
-	// No need to validate on each request - just continue processing
-	return ctx.Next()
-}
-
-// processMultiOrgPluginRequest validates license for org ID provided in header.
-func (c *LicenseClient) processMultiOrgPluginRequest(ctx *fiber.Ctx) error {
-	l := c.validator.GetLogger()
-
-	// Extract organization ID from header
-	orgID := ctx.Get(cn.OrganizationIDHeader)
-
-	// Use the shared validation function
-	res, err := c.validateOrganizationID(ctx.Context(), orgID)
-	if err != nil {
-		if err == cn.ErrMissingOrgIDHeader {
-			l.Errorf("Missing org header (code %s)", cn.ErrMissingOrgIDHeader.Error())
-			return pkgHTTP.WithError(ctx, pkg.ValidateBusinessError(err, "", cn.OrganizationIDHeader))
+		// The user context carries the request span of tracing middlewares such as otelfiber
+		decision, err := guard.Check(ctx.UserContext(), func() (string, error) {
+			// Extract organization ID (from the header unless another resolver is configured)
+			return c.resolveOrgID(ctx)
+		})
+		if err != nil {
+			return pkgHTTP.WithError(ctx, err)
 		}
 
-		if err == cn.ErrUnknownOrgIDHeader {
-			l.Errorf("Unknown org ID %s", orgID)
-			return pkgHTTP.WithError(ctx, pkg.ValidateBusinessError(err, "", orgID))
+		if decision.OrganizationID != "" {
+			ctx.Locals(OrganizationIDKey, decision.OrganizationID)
+			ctx.Locals(DecisionKey, decision)
+			ctx.SetUserContext(ContextWithDecision(ctx.UserContext(), decision))
 		}
 
-		l.Errorf("Validation failed for org %s: %v", orgID, err)
-
-		return pkgHTTP.WithError(ctx, pkg.ValidateBusinessError(err, "", orgID))
-	}
-
-	// Check if license is valid
-	if !res.Valid && !res.ActiveGracePeriod {
-		l.Errorf("Org %s license invalid", orgID)
+		for name, values := range guard.Headers(decision) {
+			ctx.Set(name, values[0])
+		}
 
-		return pkgHTTP.WithError(ctx, pkg.ValidateBusinessError(cn.ErrOrgLicenseInvalid, "", orgID))
+		return ctx.Next()
 	}
-
-	return ctx.Next()
 }
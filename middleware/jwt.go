@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/jwt"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/metadata"
+)
+
+// Defaults of the JWT organization resolver
+const (
+	defaultOrgClaim            = "organization_id"
+	defaultJWKSRefreshInterval = time.Hour
+	defaultJWKSTimeout         = 5 * time.Second
+)
+
+// JWTResolverConfig configures the organization resolver backed by verified bearer tokens.
+// At least one of Keys, JWKSFile or JWKSURL is required.
+type JWTResolverConfig struct {
+	// Claim is the token claim holding the organization ID (default "organization_id")
+	Claim string
+	// Keys are static verification keys by key ID: *rsa.PublicKey (RS256), *ecdsa.PublicKey (ES256)
+	// or ed25519.PublicKey (EdDSA)
+	Keys map[string]crypto.PublicKey
+	// JWKSFile is read once at construction
+	JWKSFile string
+	// JWKSURL is downloaded on first use and refreshed every JWKSRefreshInterval (default 1 hour),
+	// or sooner when a token names an unknown key
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	// HTTPClient downloads the JWKS (default client with a 5 second timeout)
+	HTTPClient *http.Client
+	// Issuer and Audience, when set, must match the iss and aud claims
+	Issuer   string
+	Audience string
+	// AllowMissingExpiry accepts tokens without an exp claim. By default they are rejected.
+	AllowMissingExpiry bool
+	// Header is the header or gRPC metadata key compared with the claim (default X-Organization-ID).
+	// A request sending a different organization ID there is rejected with LCS-0015.
+	Header string
+}
+
+// OrgClaimMismatchError is returned when the organization ID header differs from the token claim
+type OrgClaimMismatchError struct {
+	Claim  string
+	Header string
+}
+
+func (e *OrgClaimMismatchError) Error() string {
+	return fmt.Sprintf("%s: token organization %q does not match header organization %q", cn.ErrOrgClaimMismatch.Error(), e.Claim, e.Header)
+}
+
+// Unwrap returns cn.ErrOrgClaimMismatch
+func (e *OrgClaimMismatchError) Unwrap() error {
+	return cn.ErrOrgClaimMismatch
+}
+
+// JWTResolver reads the organization ID from a claim of the verified bearer token, so callers cannot
+// choose which tenant's license is checked. It is an OrgResolver, an HTTPOrgResolver and a GRPCOrgResolver.
+type JWTResolver struct {
+	claim  string
+	header string
+	expect jwt.Expectations
+	keys   *jwt.KeySet
+	now    func() time.Time
+}
+
+// NewJWTResolver creates a JWT organization resolver
+func NewJWTResolver(cfg JWTResolverConfig) (*JWTResolver, error) {
+	if cfg.Claim == "" {
+		cfg.Claim = defaultOrgClaim
+	}
+
+	if cfg.Header == "" {
+		cfg.Header = cn.OrganizationIDHeader
+	}
+
+	if cfg.JWKSRefreshInterval <= 0 {
+		cfg.JWKSRefreshInterval = defaultJWKSRefreshInterval
+	}
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultJWKSTimeout}
+	}
+
+	keys, err := jwt.NewKeySet(cfg.Keys, cfg.JWKSFile, cfg.JWKSURL, cfg.HTTPClient, cfg.JWKSRefreshInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWTResolver{
+		claim:  cfg.Claim,
+		header: cfg.Header,
+		expect: jwt.Expectations{Issuer: cfg.Issuer, Audience: cfg.Audience, AllowMissingExpiry: cfg.AllowMissingExpiry},
+		keys:   keys,
+		now:    time.Now,
+	}, nil
+}
+
+// Resolve reads the organization ID from the Authorization header of a Fiber request
+func (r *JWTResolver) Resolve(ctx *fiber.Ctx) (string, error) {
+	return r.resolve(ctx.Context(), ctx.Get(fiber.HeaderAuthorization), ctx.Get(r.header))
+}
+
+// ResolveHTTP reads the organization ID from the Authorization header of a net/http request
+func (r *JWTResolver) ResolveHTTP(req *http.Request) (string, error) {
+	return r.resolve(req.Context(), req.Header.Get("Authorization"), req.Header.Get(r.header))
+}
+
+// ResolveGRPC reads the organization ID from the authorization metadata of a gRPC call
+func (r *JWTResolver) ResolveGRPC(ctx context.Context, _ any) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return r.resolve(ctx, firstValue(md, "authorization"), firstValue(md, r.header))
+}
+
+// resolve verifies the bearer token and compares its organization claim with the header value
+func (r *JWTResolver) resolve(ctx context.Context, authorization, headerOrgID string) (string, error) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", fmt.Errorf("%w: missing bearer token", cn.ErrInvalidOrgToken)
+	}
+
+	claims, err := jwt.Verify(strings.TrimSpace(token), func(keyID string) []crypto.PublicKey {
+		return r.keys.Keys(ctx, keyID)
+	}, r.expect, r.now())
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", cn.ErrInvalidOrgToken, err)
+	}
+
+	orgID := strings.TrimSpace(claims.String(r.claim))
+	if orgID == "" {
+		return "", fmt.Errorf("%w: token has no %s claim", cn.ErrInvalidOrgToken, r.claim)
+	}
+
+	if headerOrgID = strings.TrimSpace(headerOrgID); headerOrgID != "" && headerOrgID != orgID {
+		return "", &OrgClaimMismatchError{Claim: orgID, Header: headerOrgID}
+	}
+
+	return orgID, nil
+}
+
+// firstValue returns the first metadata value of key, or an empty string
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
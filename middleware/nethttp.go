@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	pkgHTTP "github.com/LerianStudio/lib-license-go/pkg/net/http"
+)
+
+// HTTPOrgResolver extracts the organization ID from a net/http request.
+// It returns an empty string when its source does not carry an organization ID.
+type HTTPOrgResolver interface {
+	ResolveHTTP(r *http.Request) (string, error)
+}
+
+// HTTPOrgResolverFunc adapts a function to the HTTPOrgResolver interface
+type HTTPOrgResolverFunc func(r *http.Request) (string, error)
+
+// ResolveHTTP calls f(r)
+func (f HTTPOrgResolverFunc) ResolveHTTP(r *http.Request) (string, error) {
+	return f(r)
+}
+
+// HTTPMiddleware creates a net/http middleware that validates the license like Middleware does for Fiber.
+// It can wrap any http.Handler, including chi and gorilla/mux routers, and responds with the same
+// LCS error bodies and status codes. Startup validation and background refresh are shared with the
+// Fiber middleware and the gRPC interceptors.
+func (c *LicenseClient) HTTPMiddleware(next http.Handler) http.Handler {
+	guard := c.Guard()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decision, err := guard.CheckHTTP(r)
+		if err != nil {
+			pkgHTTP.WriteError(w, err)
+			return
+		}
+
+		if decision.OrganizationID != "" {
+			r = r.WithContext(ContextWithDecision(r.Context(), decision))
+		}
+
+		for name, values := range guard.Headers(decision) {
+			w.Header()[name] = values
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SetHTTPOrgResolver changes how HTTPMiddleware extracts the organization ID from requests.
+// The default reads the X-Organization-ID header. It must be called before serving requests.
+func (c *LicenseClient) SetHTTPOrgResolver(resolver HTTPOrgResolver) {
+	if c != nil {
+		c.httpOrgResolver = resolver
+	}
+}
+
+// resolveHTTPOrgID extracts the organization ID of a net/http request with the configured resolver
+func (c *LicenseClient) resolveHTTPOrgID(r *http.Request) (string, error) {
+	if c.httpOrgResolver == nil {
+		return r.Header.Get(cn.OrganizationIDHeader), nil
+	}
+
+	return c.httpOrgResolver.ResolveHTTP(r)
+}
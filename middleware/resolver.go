@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/gofiber/fiber/v2"
+)
+
+// OrgResolver extracts the organization ID from a Fiber request.
+// It returns an empty string when its source does not carry an organization ID.
+type OrgResolver interface {
+	Resolve(ctx *fiber.Ctx) (string, error)
+}
+
+// OrgResolverFunc adapts a function to the OrgResolver interface
+type OrgResolverFunc func(ctx *fiber.Ctx) (string, error)
+
+// Resolve calls f(ctx)
+func (f OrgResolverFunc) Resolve(ctx *fiber.Ctx) (string, error) {
+	return f(ctx)
+}
+
+// OrgIDConflictError is returned by a resolver chain when two sources carry different organization IDs
+type OrgIDConflictError struct {
+	First  string
+	Second string
+}
+
+func (e *OrgIDConflictError) Error() string {
+	return fmt.Sprintf("%s: conflicting organization IDs %q and %q", cn.ErrConflictingOrgID.Error(), e.First, e.Second)
+}
+
+// Unwrap returns cn.ErrConflictingOrgID
+func (e *OrgIDConflictError) Unwrap() error {
+	return cn.ErrConflictingOrgID
+}
+
+// HeaderResolver reads the organization ID from a request header
+func HeaderResolver(name string) OrgResolver {
+	return OrgResolverFunc(func(ctx *fiber.Ctx) (string, error) {
+		return strings.TrimSpace(ctx.Get(name)), nil
+	})
+}
+
+// PathParamResolver reads the organization ID from a route parameter, e.g. "organization_id"
+// for routes like /v1/organizations/:organization_id. The resolver must run after routing,
+// so register the middleware on the route or group that declares the parameter.
+func PathParamResolver(name string) OrgResolver {
+	return OrgResolverFunc(func(ctx *fiber.Ctx) (string, error) {
+		return strings.TrimSpace(ctx.Params(name)), nil
+	})
+}
+
+// QueryResolver reads the organization ID from a query string parameter
+func QueryResolver(name string) OrgResolver {
+	return OrgResolverFunc(func(ctx *fiber.Ctx) (string, error) {
+		return strings.TrimSpace(ctx.Query(name)), nil
+	})
+}
+
+// SubdomainResolver reads the organization ID from the subdomain label directly below baseDomain,
+// e.g. "acme" for acme.api.example.com with baseDomain api.example.com
+func SubdomainResolver(baseDomain string) OrgResolver {
+	suffix := "." + strings.Trim(strings.ToLower(baseDomain), ".")
+
+	return OrgResolverFunc(func(ctx *fiber.Ctx) (string, error) {
+		host := strings.ToLower(ctx.Hostname())
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		prefix, ok := strings.CutSuffix(host, suffix)
+		if !ok || prefix == "" {
+			return "", nil
+		}
+
+		labels := strings.Split(prefix, ".")
+
+		return labels[len(labels)-1], nil
+	})
+}
+
+// LocalsResolver reads the organization ID stored in fiber.Ctx.Locals under key, typically by an
+// authentication middleware registered before the license middleware. The value must be a string
+// or a fmt.Stringer.
+func LocalsResolver(key any) OrgResolver {
+	return OrgResolverFunc(func(ctx *fiber.Ctx) (string, error) {
+		switch v := ctx.Locals(key).(type) {
+		case nil:
+			return "", nil
+		case string:
+			return strings.TrimSpace(v), nil
+		case fmt.Stringer:
+			return strings.TrimSpace(v.String()), nil
+		default:
+			return "", fmt.Errorf("locals key %v holds %T, not an organization ID", key, v)
+		}
+	})
+}
+
+// ChainResolvers combines resolvers in priority order. The first organization ID found is used,
+// and the request is rejected with an *OrgIDConflictError when another source carries a different one.
+func ChainResolvers(resolvers ...OrgResolver) OrgResolver {
+	return OrgResolverFunc(func(ctx *fiber.Ctx) (string, error) {
+		var orgID string
+
+		for _, resolver := range resolvers {
+			value, err := resolver.Resolve(ctx)
+			if err != nil {
+				return "", err
+			}
+
+			if value == "" {
+				continue
+			}
+
+			if orgID == "" {
+				orgID = value
+				continue
+			}
+
+			if value != orgID {
+				return "", &OrgIDConflictError{First: orgID, Second: value}
+			}
+		}
+
+		return orgID, nil
+	})
+}
+
+// SetOrgResolver changes how the Fiber middleware extracts the organization ID from requests.
+// The default reads the X-Organization-ID header. It must be called before serving requests.
+func (c *LicenseClient) SetOrgResolver(resolver OrgResolver) {
+	if c != nil {
+		c.orgResolver = resolver
+	}
+}
+
+// resolveOrgID extracts the organization ID of the request with the configured resolver
+func (c *LicenseClient) resolveOrgID(ctx *fiber.Ctx) (string, error) {
+	if c.orgResolver == nil {
+		return ctx.Get(cn.OrganizationIDHeader), nil
+	}
+
+	return c.orgResolver.Resolve(ctx)
+}
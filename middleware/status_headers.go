@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// License status values of the X-License-Status header, the states of accepted licenses
+const (
+	// LicenseStatusActive is a valid license outside the expiry warning threshold
+	LicenseStatusActive = string(model.StateActive)
+	// LicenseStatusExpiring is a valid license within the expiry warning threshold
+	LicenseStatusExpiring = string(model.StateExpiring)
+	// LicenseStatusTrial is a valid trial license
+	LicenseStatusTrial = string(model.StateTrial)
+	// LicenseStatusGrace is an expired license in its grace period
+	LicenseStatusGrace = string(model.StateGrace)
+)
+
+// StatusHeaders configures the license status headers added to accepted responses, so clients and
+// UIs can show expiry, trial and grace period banners without calling another API
+type StatusHeaders struct {
+	// Prefix of the header names; empty uses cn.DefaultLicenseHeaderPrefix ("X-License-")
+	Prefix string
+	// WarningsOnly adds the headers only when the license is within a warning threshold of
+	// constant/license.go: a trial ending within DefaultTrialExpiryDaysToWarn days, a license expiring
+	// within DefaultMinExpiryDaysToNormalWarn days, or an active grace period
+	WarningsOnly bool
+}
+
+// SetStatusHeaders makes the HTTP middlewares add the license status headers to accepted responses,
+// and the gRPC server interceptors send them as header metadata (lowercased, as gRPC requires).
+// They are derived from the cached validation result of the request's organization (of the application
+// in global mode) and are not added to exempt requests. Call it before serving requests.
+func (c *LicenseClient) SetStatusHeaders(headers StatusHeaders) {
+	if c == nil {
+		return
+	}
+
+	if headers.Prefix == "" {
+		headers.Prefix = cn.DefaultLicenseHeaderPrefix
+	}
+
+	c.statusHeaders = &headers
+}
+
+// Headers returns the license status headers of an accepted request, or nil when they are disabled
+// or not due. decision is the one returned by Check.
+func (g *Guard) Headers(decision Decision) http.Header {
+	return g.client.licenseHeaders(decision)
+}
+
+// licenseHeaders builds the license status headers of an accepted request
+func (c *LicenseClient) licenseHeaders(decision Decision) http.Header {
+	config := c.statusHeaders
+	if config == nil {
+		return nil
+	}
+
+	result := decision.Result
+
+	if decision.OrganizationID == "" {
+		// Exempt requests carry no decision
+		if !decision.global {
+			return nil
+		}
+
+		cached, found := c.validator.CachedResult(cn.GlobalPluginValue)
+		if !found {
+			return nil
+		}
+
+		result = cached
+	}
+
+	if config.WarningsOnly && !licenseWarning(result) {
+		return nil
+	}
+
+	headers := make(http.Header, 4)
+	headers.Set(config.Prefix+cn.LicenseStatusHeader, string(result.State()))
+	headers.Set(config.Prefix+cn.LicenseExpiresInDaysHeader, strconv.Itoa(result.ExpiryDaysLeft))
+	headers.Set(config.Prefix+cn.LicenseGracePeriodHeader, strconv.FormatBool(result.ActiveGracePeriod))
+	headers.Set(config.Prefix+cn.LicenseTrialHeader, strconv.FormatBool(result.IsTrial))
+
+	return headers
+}
+
+// licenseMetadata returns the license status headers of an accepted gRPC call as metadata
+func (c *LicenseClient) licenseMetadata(decision Decision) metadata.MD {
+	headers := c.licenseHeaders(decision)
+	if headers == nil {
+		return nil
+	}
+
+	md := make(metadata.MD, len(headers))
+	for name, values := range headers {
+		md.Append(name, values...)
+	}
+
+	return md
+}
+
+// setUnaryLicenseHeader sends the license status headers of an accepted unary call as header metadata
+func (c *LicenseClient) setUnaryLicenseHeader(ctx context.Context, decision Decision) {
+	if md := c.licenseMetadata(decision); md != nil {
+		if err := grpc.SetHeader(ctx, md); err != nil {
+			c.validator.GetLogger().Warnf("Failed to set license status headers: %v", err)
+		}
+	}
+}
+
+// setStreamLicenseHeader sends the license status headers of an accepted stream as header metadata.
+// Streams whose organization ID only arrives with the first message have no decision yet and get none.
+func (c *LicenseClient) setStreamLicenseHeader(ss grpc.ServerStream, decision Decision) {
+	if md := c.licenseMetadata(decision); md != nil {
+		if err := ss.SetHeader(md); err != nil {
+			c.validator.GetLogger().Warnf("Failed to set license status headers: %v", err)
+		}
+	}
+}
+
+// licenseWarning reports whether an accepted license is within a warning threshold, matching the
+// thresholds at which the validation client logs warnings
+func licenseWarning(result model.ValidationResult) bool {
+	switch {
+	case result.ActiveGracePeriod:
+		return true
+	case result.IsTrial:
+		return result.ExpiryDaysLeft <= cn.DefaultTrialExpiryDaysToWarn
+	default:
+		return result.ExpiryDaysLeft <= cn.DefaultMinExpiryDaysToNormalWarn
+	}
+}
@@ -0,0 +1,23 @@
+package model
+
+// OrganizationEventType identifies a change to the set of organizations served by the client
+type OrganizationEventType string
+
+const (
+	// OrganizationAdded is emitted when an organization passed validation and was added at runtime
+	OrganizationAdded OrganizationEventType = "organization_added"
+	// OrganizationRejected is emitted when an organization could not be added because its license is not valid
+	OrganizationRejected OrganizationEventType = "organization_rejected"
+	// OrganizationRemoved is emitted when an organization was removed at runtime
+	OrganizationRemoved OrganizationEventType = "organization_removed"
+)
+
+// OrganizationEvent describes a runtime change to the organizations served by the client
+type OrganizationEvent struct {
+	Type           OrganizationEventType `json:"type"`
+	OrganizationID string                `json:"organizationId"`
+	// Result is the validation result of an added or rejected organization
+	Result ValidationResult `json:"result"`
+	// Err is the reason a rejected organization was not added
+	Err error `json:"-"`
+}
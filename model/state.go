@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+)
+
+// LicenseState is the license state of an organization, as last seen by the client
+type LicenseState string
+
+const (
+	// StateUnknown is the state of an organization that has not been validated yet
+	StateUnknown LicenseState = "unknown"
+	// StateActive is a valid license outside the expiry warning threshold
+	StateActive LicenseState = "active"
+	// StateExpiring is a valid license expiring within DefaultMinExpiryDaysToNormalWarn days
+	StateExpiring LicenseState = "expiring"
+	// StateTrial is a valid trial license
+	StateTrial LicenseState = "trial"
+	// StateGrace is an expired license in its grace period
+	StateGrace LicenseState = "grace"
+	// StateExpired is a license that expired with no grace period left
+	StateExpired LicenseState = "expired"
+	// StateOffline means the license provider could not be reached or answered with a server error
+	StateOffline LicenseState = "offline"
+	// StateRevoked means the license provider rejected the license
+	StateRevoked LicenseState = "revoked"
+)
+
+// State returns the license state described by a validation result
+func (r ValidationResult) State() LicenseState {
+	switch {
+	case r.ActiveGracePeriod:
+		return StateGrace
+	case !r.Valid:
+		return StateExpired
+	case r.IsTrial:
+		return StateTrial
+	case r.ExpiryDaysLeft <= cn.DefaultMinExpiryDaysToNormalWarn:
+		return StateExpiring
+	default:
+		return StateActive
+	}
+}
+
+// StateTransition describes the change of the license state of an organization
+type StateTransition struct {
+	OrganizationID string       `json:"organizationId"`
+	From           LicenseState `json:"from"`
+	To             LicenseState `json:"to"`
+	// Result is the validation result that caused the transition; it is empty when the provider failed
+	Result ValidationResult `json:"result"`
+	// Err is the failure that caused a transition to StateOffline or StateRevoked
+	Err error     `json:"-"`
+	At  time.Time `json:"at"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// ClientMode is the mode a license client runs in
+type ClientMode string
+
+const (
+	// ModeGlobal validates a single application-wide license
+	ModeGlobal ClientMode = "global"
+	// ModeMultiOrganization validates the license of each configured organization
+	ModeMultiOrganization ClientMode = "multi-organization"
+)
+
+// ResultSource tells where the last result of an organization came from
+type ResultSource string
+
+const (
+	// SourceAPI is a result answered by the license provider
+	SourceAPI ResultSource = "api"
+	// SourceCache is a result served from the cache on the request path
+	SourceCache ResultSource = "cache"
+	// SourceFallback is a result assumed while the license provider was unavailable: a cached result,
+	// the persisted snapshot or the fallback grace days
+	SourceFallback ResultSource = "fallback"
+)
+
+// OrganizationStatus is what the client currently believes about the license of an organization
+type OrganizationStatus struct {
+	OrganizationID string       `json:"organizationId"`
+	State          LicenseState `json:"state"`
+	// Result is the last result of the organization; it is empty when the last validation failed
+	Result ValidationResult `json:"result"`
+	// Source is empty until the organization is validated
+	Source ResultSource `json:"source,omitempty"`
+	// ValidatedAt is when the license provider was last asked about the organization, successfully or not
+	ValidatedAt time.Time `json:"validatedAt"`
+	// Error describes the failure of the last validation
+	Error string `json:"error,omitempty"`
+}
+
+// ClientStatus is a point-in-time snapshot of the license client, e.g. for admin endpoints and support bundles
+type ClientStatus struct {
+	Mode ClientMode `json:"mode"`
+	// Endpoint is the license server URL; it is empty when a custom provider or an offline license file is used
+	Endpoint string `json:"endpoint,omitempty"`
+	// Organizations lists the served organizations in configuration order
+	Organizations []OrganizationStatus `json:"organizations"`
+	// LastRefreshAttempt and LastSuccessfulRefresh are zero until the background refresh runs
+	LastRefreshAttempt    time.Time `json:"lastRefreshAttempt"`
+	LastSuccessfulRefresh time.Time `json:"lastSuccessfulRefresh"`
+	// NextRefresh is zero while the background refresh is stopped
+	NextRefresh time.Time `json:"nextRefresh"`
+	GeneratedAt time.Time `json:"generatedAt"`
+}
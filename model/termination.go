@@ -0,0 +1,40 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TerminationCause identifies the fatal license outcome that triggered a termination
+type TerminationCause string
+
+const (
+	// TerminationCauseStartupFailure means no organization had a valid license at startup
+	TerminationCauseStartupFailure TerminationCause = "startup_failure"
+	// TerminationCauseLicenseRevoked means a background refresh found the licenses rejected by the server
+	TerminationCauseLicenseRevoked TerminationCause = "license_revoked"
+	// TerminationCauseGracePeriodExhausted means a background refresh found the licenses expired with no grace period left
+	TerminationCauseGracePeriodExhausted TerminationCause = "grace_period_exhausted"
+)
+
+// TerminationReason describes why the license client asked the application to terminate
+type TerminationReason struct {
+	Code            string           `json:"code"`
+	Cause           TerminationCause `json:"cause"`
+	Message         string           `json:"message"`
+	OrganizationIDs []string         `json:"organizationIds,omitempty"`
+	// LastStatusCode and LastResponse hold the last error answered by the license server, if any
+	LastStatusCode int            `json:"lastStatusCode,omitempty"`
+	LastResponse   *ErrorResponse `json:"lastResponse,omitempty"`
+}
+
+// String renders the reason as passed to lib-commons termination handlers
+func (r TerminationReason) String() string {
+	reason := fmt.Sprintf("%s [%s]: %s", r.Code, r.Cause, r.Message)
+
+	if len(r.OrganizationIDs) > 0 {
+		reason = fmt.Sprintf("%s (organizations: %s)", reason, strings.Join(r.OrganizationIDs, ", "))
+	}
+
+	return reason
+}
@@ -14,3 +14,8 @@ type ErrorResponse struct {
 	Title   string `json:"title"`
 	Message string `json:"message"`
 }
+
+// OrganizationsResponse contains the organizations a license key entitles for an application
+type OrganizationsResponse struct {
+	OrganizationIDs []string `json:"organizationIds"`
+}
@@ -113,6 +113,11 @@ func (e ForbiddenError) Error() string {
 	return e.Message
 }
 
+// Unwrap implements the error interface introduced in Go 1.13 to unwrap the internal error.
+func (e ForbiddenError) Unwrap() error {
+	return e.Err
+}
+
 // UnprocessableOperationError indicates an operation that couldn't be performant because it's invalid.
 type UnprocessableOperationError struct {
 	EntityType string
@@ -281,6 +286,36 @@ func ValidateBusinessError(err error, entityType string, args ...any) error {
 			Title:      "Organization license is invalid",
 			Message:    fmt.Sprintf("The license for organization ID '%s' is not valid and has no grace period active. Please renew your license or contact support for assistance.", args...),
 		},
+		constant.ErrConflictingOrgID: ValidationError{
+			EntityType: entityType,
+			Code:       constant.ErrConflictingOrgID.Error(),
+			Title:      "Conflicting organization IDs",
+			Message:    fmt.Sprintf("The request carries conflicting organization IDs '%s' and '%s'. Please send a single organization ID.", args...),
+		},
+		constant.ErrOrgClaimMismatch: ForbiddenError{
+			EntityType: entityType,
+			Code:       constant.ErrOrgClaimMismatch.Error(),
+			Title:      "Organization ID does not match the token",
+			Message:    fmt.Sprintf("The token grants access to organization ID '%s' but the request targets '%s'.", args...),
+		},
+		constant.ErrInvalidOrgToken: UnauthorizedError{
+			EntityType: entityType,
+			Code:       constant.ErrInvalidOrgToken.Error(),
+			Title:      "Invalid token",
+			Message:    "The bearer token is missing, invalid or does not carry an organization ID. Please authenticate with a valid token.",
+		},
+		constant.ErrAdminForbidden: ForbiddenError{
+			EntityType: entityType,
+			Code:       constant.ErrAdminForbidden.Error(),
+			Title:      "Admin request not authorized",
+			Message:    "You are not authorized to use the license admin endpoint.",
+		},
+		constant.ErrInvalidAdminRequest: ValidationError{
+			EntityType: entityType,
+			Code:       constant.ErrInvalidAdminRequest.Error(),
+			Title:      "Invalid admin request",
+			Message:    fmt.Sprintf("The admin request is invalid: %s.", args...),
+		},
 	}
 
 	if mappedError, found := errorMap[err]; found {
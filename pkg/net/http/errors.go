@@ -1,48 +1,72 @@
 package http
 
 import (
+	"encoding/json"
 	"errors"
 	"net"
+	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/LerianStudio/lib-commons/commons"
-	commonsHttp "github.com/LerianStudio/lib-commons/commons/net/http"
 	"github.com/LerianStudio/lib-license-go/pkg"
 	"github.com/gofiber/fiber/v2"
 )
 
 // WithError returns an error with the given status code and message.
 func WithError(c *fiber.Ctx, err error) error {
+	status, body := ErrorResponse(err)
+
+	return c.Status(status).JSON(body)
+}
+
+// WriteError writes the same JSON error response as WithError to a net/http response writer.
+func WriteError(w http.ResponseWriter, err error) {
+	status, body := ErrorResponse(err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// ErrorResponse returns the HTTP status code and JSON body rendered for an error.
+func ErrorResponse(err error) (int, any) {
 	switch e := err.(type) {
 	case pkg.EntityNotFoundError:
-		return commonsHttp.NotFound(c, e.Code, e.Title, e.Message)
+		return http.StatusNotFound, commons.Response{Code: e.Code, Title: e.Title, Message: e.Message}
 	case pkg.EntityConflictError:
-		return commonsHttp.Conflict(c, e.Code, e.Title, e.Message)
+		return http.StatusConflict, commons.Response{Code: e.Code, Title: e.Title, Message: e.Message}
 	case pkg.ValidationError:
-		return commonsHttp.BadRequest(c, pkg.ValidationKnownFieldsError{
+		return http.StatusBadRequest, pkg.ValidationKnownFieldsError{
 			Code:    e.Code,
 			Title:   e.Title,
 			Message: e.Message,
 			Fields:  nil,
-		})
+		}
 	case pkg.UnprocessableOperationError:
-		return commonsHttp.UnprocessableEntity(c, e.Code, e.Title, e.Message)
+		return http.StatusUnprocessableEntity, commons.Response{Code: e.Code, Title: e.Title, Message: e.Message}
 	case pkg.UnauthorizedError:
-		return commonsHttp.Unauthorized(c, e.Code, e.Title, e.Message)
+		return http.StatusUnauthorized, commons.Response{Code: e.Code, Title: e.Title, Message: e.Message}
 	case pkg.ForbiddenError:
-		return commonsHttp.Forbidden(c, e.Code, e.Title, e.Message)
+		return http.StatusForbidden, commons.Response{Code: e.Code, Title: e.Title, Message: e.Message}
 	case pkg.ValidationKnownFieldsError, pkg.ValidationUnknownFieldsError:
-		return commonsHttp.BadRequest(c, e)
+		return http.StatusBadRequest, e
 	case pkg.ResponseError:
 		var rErr commons.Response
 		_ = errors.As(err, &rErr)
 
-		return commonsHttp.JSONResponseError(c, rErr)
+		status, convErr := strconv.Atoi(rErr.Code)
+		if convErr != nil || status < http.StatusContinue {
+			status = http.StatusInternalServerError
+		}
+
+		return status, rErr
 	default:
 		var iErr pkg.InternalServerError
 		_ = errors.As(pkg.ValidateInternalError(err, ""), &iErr)
 
-		return commonsHttp.InternalServerError(c, iErr.Code, iErr.Title, iErr.Message)
+		return http.StatusInternalServerError, commons.Response{Code: iErr.Code, Title: iErr.Title, Message: iErr.Message}
 	}
 }
 
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+	pkgHTTP "github.com/LerianStudio/lib-license-go/pkg/net/http"
+)
+
+// Chain tries its providers in order and returns the first answer, which is either a result or a definitive
+// rejection. It only fails over to the next provider when the current one is temporarily unavailable (5xx
+// response, connection failure or timeout), so a revoked license is never turned into a valid one by a
+// fallback provider. When every provider is unavailable, the error of the first provider is returned so the
+// primary source decides how the failure is handled (for instance a 5xx fallback).
+type Chain struct {
+	providers []LicenseProvider
+}
+
+// NewChain creates a failover provider over the given providers, in priority order
+func NewChain(providers ...LicenseProvider) *Chain {
+	return &Chain{providers: providers}
+}
+
+// Validate validates the organization with the first provider able to answer
+func (c *Chain) Validate(ctx context.Context, orgID string) (model.ValidationResult, error) {
+	if len(c.providers) == 0 {
+		return model.ValidationResult{}, errors.New("license provider chain is empty")
+	}
+
+	var firstErr error
+
+	for _, p := range c.providers {
+		result, err := p.Validate(ctx, orgID)
+		if err == nil {
+			return result, nil
+		}
+
+		// Rejections and unexpected failures are the answer of the provider
+		if !isUnavailable(err) {
+			return model.ValidationResult{}, err
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		// Stop failing over once the caller gave up
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return model.ValidationResult{}, firstErr
+}
+
+// isUnavailable reports whether a provider error means the provider is temporarily unavailable,
+// as opposed to a definitive rejection of the organization
+func isUnavailable(err error) bool {
+	var forbiddenErr pkg.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return false
+	}
+
+	var httpErr *pkg.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+
+	return pkgHTTP.IsServerError(err) || pkgHTTP.IsConnectionError(err)
+}
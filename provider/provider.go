@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	"github.com/LerianStudio/lib-commons/commons/zap"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/api"
+	"github.com/LerianStudio/lib-license-go/internal/config"
+	"github.com/LerianStudio/lib-license-go/internal/offline"
+	"github.com/LerianStudio/lib-license-go/model"
+)
+
+// LicenseProvider is a source of truth for organization licenses.
+// An error that wraps a pkg.ForbiddenError, or a 4xx *pkg.HTTPError, is treated as a definitive rejection;
+// a 5xx *pkg.HTTPError or a connection error is treated as the source being temporarily unavailable.
+type LicenseProvider interface {
+	Validate(ctx context.Context, orgID string) (model.ValidationResult, error)
+}
+
+// BatchProvider is implemented by providers that can validate several organizations in a single call.
+// Organizations missing from the returned results, or all of them when an error is returned,
+// are validated one by one with Validate.
+type BatchProvider interface {
+	LicenseProvider
+	ValidateBatch(ctx context.Context, orgIDs []string) (map[string]model.ValidationResult, error)
+}
+
+// Discoverer is implemented by providers that can list the organizations the license key entitles,
+// which is required by the organization discovery mode
+type Discoverer interface {
+	Organizations(ctx context.Context) ([]string, error)
+}
+
+// NewHTTP creates a provider backed by the Lerian license server. This is the default provider.
+// An empty baseURL is resolved from LICENSE_URL, LICENSE_PROFILE and IS_DEVELOPMENT like the default client,
+// and a nil httpClient is replaced by one using the default timeout.
+func NewHTTP(appName, licenseKey, baseURL string, httpClient *http.Client, logger *log.Logger) (LicenseProvider, error) {
+	if appName == "" {
+		return nil, errors.New("application name is required")
+	}
+
+	cfg := &config.ClientConfig{
+		AppName:     appName,
+		LicenseKey:  licenseKey,
+		BaseURL:     baseURL,
+		HTTPTimeout: cn.DefaultHTTPTimeoutSeconds * time.Second,
+	}
+
+	if err := cfg.ResolveBaseURL(); err != nil {
+		return nil, err
+	}
+
+	return api.New(cfg, httpClient, loggerOrDefault(logger)), nil
+}
+
+// NewFile creates a provider backed by a signed offline license file verified with the given Ed25519 keys
+func NewFile(appName, path string, publicKeys map[string]ed25519.PublicKey, logger *log.Logger) (LicenseProvider, error) {
+	if appName == "" {
+		return nil, errors.New("application name is required")
+	}
+
+	if path == "" {
+		return nil, errors.New("license file path is required")
+	}
+
+	if len(publicKeys) == 0 {
+		return nil, errors.New("at least one public key is required to verify the license file")
+	}
+
+	if err := config.ValidatePublicKeys(publicKeys); err != nil {
+		return nil, err
+	}
+
+	cfg := &config.ClientConfig{
+		AppName:     appName,
+		LicenseFile: path,
+		PublicKeys:  publicKeys,
+	}
+
+	return offline.New(cfg, loggerOrDefault(logger)), nil
+}
+
+// loggerOrDefault dereferences the logger, falling back to a new zap logger when it is nil
+func loggerOrDefault(logger *log.Logger) log.Logger {
+	if logger != nil {
+		return *logger
+	}
+
+	return zap.InitializeLogger()
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+)
+
+// devExpiryDaysLeft is the expiry reported by the development provider
+const devExpiryDaysLeft = 365
+
+// Static serves fixed validation results from memory. It is meant for development and tests.
+type Static struct {
+	results  map[string]model.ValidationResult
+	fallback *model.ValidationResult
+}
+
+// NewStatic creates a provider returning the given result for each organization.
+// Organizations without a result are rejected.
+func NewStatic(results map[string]model.ValidationResult) *Static {
+	copied := make(map[string]model.ValidationResult, len(results))
+	for orgID, result := range results {
+		copied[orgID] = result
+	}
+
+	return &Static{results: copied}
+}
+
+// NewDev creates a provider that reports a valid license for every organization.
+// It must never be used in production.
+func NewDev() *Static {
+	return &Static{
+		results: map[string]model.ValidationResult{},
+		fallback: &model.ValidationResult{
+			Valid:          true,
+			ExpiryDaysLeft: devExpiryDaysLeft,
+		},
+	}
+}
+
+// Validate returns the configured result of the organization
+func (s *Static) Validate(_ context.Context, orgID string) (model.ValidationResult, error) {
+	if result, ok := s.results[orgID]; ok {
+		return result, nil
+	}
+
+	if s.fallback != nil {
+		return *s.fallback, nil
+	}
+
+	return model.ValidationResult{}, pkg.ForbiddenError{
+		Code:    cn.ErrOrgLicenseInvalid.Error(),
+		Title:   "Organization license is invalid",
+		Message: fmt.Sprintf("No license is configured for organization ID '%s'.", orgID),
+	}
+}
+
+// ValidateBatch returns the configured results of the organizations that have one
+func (s *Static) ValidateBatch(ctx context.Context, orgIDs []string) (map[string]model.ValidationResult, error) {
+	results := make(map[string]model.ValidationResult, len(orgIDs))
+
+	for _, orgID := range orgIDs {
+		if result, err := s.Validate(ctx, orgID); err == nil {
+			results[orgID] = result
+		}
+	}
+
+	return results, nil
+}
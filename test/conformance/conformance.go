@@ -0,0 +1,169 @@
+// Package conformance provides the scenarios every framework adapter of the license middleware must pass.
+// The adapters that live in their own modules import it so they all run the same table.
+package conformance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Seen records the organization ID a handler found in the framework store and in the request context,
+// and the license decision found in both
+type Seen struct {
+	Store         any
+	Context       string
+	StoreDecision any
+	Decision      middleware.Decision
+}
+
+// Adapter mounts the license middleware of one framework in front of a handler that fills seen.
+// Build returns a function serving a request through the mounted middleware.
+type Adapter struct {
+	Name  string
+	Build func(t *testing.T, client *middleware.LicenseClient, seen *Seen) func(req *http.Request) *http.Response
+}
+
+// Record serves requests to a net/http handler through a response recorder
+func Record(handler http.Handler) func(req *http.Request) *http.Response {
+	return func(req *http.Request) *http.Response {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Result()
+	}
+}
+
+// scenario is a request to an adapter and the response every adapter must give
+type scenario struct {
+	name           string
+	result         model.ValidationResult
+	orgIDs         string
+	orgID          string
+	expectedStatus int
+	expectedCode   string
+	expectedOrgID  string
+}
+
+var scenarios = []scenario{
+	{
+		name:           "Valid organization",
+		result:         model.ValidationResult{Valid: true, ExpiryDaysLeft: 90},
+		orgIDs:         "org-a,org-b",
+		orgID:          "org-b",
+		expectedStatus: http.StatusOK,
+		expectedOrgID:  "org-b",
+	},
+	{
+		name:           "Missing organization header",
+		result:         model.ValidationResult{Valid: true, ExpiryDaysLeft: 90},
+		orgIDs:         "org-a",
+		expectedStatus: http.StatusBadRequest,
+		expectedCode:   cn.ErrMissingOrgIDHeader.Error(),
+	},
+	{
+		name:           "Unknown organization",
+		result:         model.ValidationResult{Valid: true, ExpiryDaysLeft: 90},
+		orgIDs:         "org-a",
+		orgID:          "org-x",
+		expectedStatus: http.StatusBadRequest,
+		expectedCode:   cn.ErrUnknownOrgIDHeader.Error(),
+	},
+	{
+		name:           "Startup validation failed",
+		result:         model.ValidationResult{Valid: false},
+		orgIDs:         "org-a",
+		orgID:          "org-a",
+		expectedStatus: http.StatusBadRequest,
+		expectedCode:   cn.ErrNoValidLicenses.Error(),
+	},
+	{
+		name:           "Global mode",
+		result:         model.ValidationResult{Valid: true, ExpiryDaysLeft: 90},
+		orgIDs:         cn.GlobalPluginValue,
+		expectedStatus: http.StatusOK,
+	},
+}
+
+// Run runs the same scenarios against every adapter
+func Run(t *testing.T, adapters ...Adapter) {
+	t.Helper()
+
+	for _, adapter := range adapters {
+		t.Run(adapter.Name, func(t *testing.T) {
+			for _, sc := range scenarios {
+				t.Run(sc.name, func(t *testing.T) {
+					client := newLicenseClient(t, sc.result, sc.orgIDs)
+
+					var seen Seen
+
+					serve := adapter.Build(t, client, &seen)
+
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					if sc.orgID != "" {
+						req.Header.Set(cn.OrganizationIDHeader, sc.orgID)
+					}
+
+					resp := serve(req)
+					defer resp.Body.Close()
+
+					assert.Equal(t, sc.expectedStatus, resp.StatusCode)
+
+					if sc.expectedCode == "" {
+						if sc.expectedOrgID != "" {
+							assert.Equal(t, sc.expectedOrgID, seen.Store)
+							assert.Equal(t, seen.Decision, seen.StoreDecision)
+							assert.True(t, seen.Decision.Result.Valid)
+							assert.Equal(t, sc.result.ExpiryDaysLeft, seen.Decision.Result.ExpiryDaysLeft)
+						} else {
+							assert.Nil(t, seen.Store)
+							assert.Nil(t, seen.StoreDecision)
+						}
+
+						assert.Equal(t, sc.expectedOrgID, seen.Context)
+						assert.Equal(t, sc.expectedOrgID, seen.Decision.OrganizationID)
+
+						return
+					}
+
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+					assert.Equal(t, sc.expectedCode, body["code"])
+					assert.NotEmpty(t, body["title"])
+					assert.NotEmpty(t, body["message"])
+				})
+			}
+		})
+	}
+}
+
+// newLicenseClient creates a license client validating against a server answering result for every organization
+func newLicenseClient(t *testing.T, result model.ValidationResult, orgIDs string) *middleware.LicenseClient {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	t.Cleanup(ts.Close)
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient("test-app", "test-key", orgIDs, &l, validation.WithLicenseURL(ts.URL))
+	require.NotNil(t, client)
+
+	client.SetHTTPClient(ts.Client())
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	return client
+}
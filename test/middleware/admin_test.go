@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler(t *testing.T) {
+	ts := orgLicenseServer(t, nil, map[string]model.ValidationResult{
+		"org-a": {Valid: true, ExpiryDaysLeft: 90},
+		"org-b": {Valid: true, ExpiryDaysLeft: 60},
+	})
+	defer ts.Close()
+
+	client, _ := newMetricsTestClient(t, ts, "org-a,org-b")
+	require.NoError(t, client.Start(context.Background()))
+
+	authorize := func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer support" {
+			return errors.New("invalid admin token")
+		}
+
+		return nil
+	}
+
+	app := fiber.New()
+	app.All("/internal/license", client.AdminHandler(authorize))
+	app.All("/internal/license/public", client.AdminHandler(nil))
+
+	mux := http.NewServeMux()
+	mux.Handle("/internal/license", client.HTTPAdminHandler(authorize))
+	mux.Handle("/internal/license/public", client.HTTPAdminHandler(nil))
+
+	servers := map[string]func(req *http.Request) *http.Response{
+		"fiber": func(req *http.Request) *http.Response {
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			return resp
+		},
+		"net/http": func(req *http.Request) *http.Response {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			return rec.Result()
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		token          string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "Status", method: http.MethodGet, target: "/internal/license", token: "support", expectedStatus: http.StatusOK},
+		{name: "Status without authorizer", method: http.MethodGet, target: "/internal/license/public", expectedStatus: http.StatusForbidden, expectedCode: "LCS-0020"},
+		{name: "Unauthorized status", method: http.MethodGet, target: "/internal/license", token: "guess", expectedStatus: http.StatusForbidden, expectedCode: "LCS-0020"},
+		{name: "Action without authorizer", method: http.MethodPost, target: "/internal/license/public?action=refresh", expectedStatus: http.StatusForbidden, expectedCode: "LCS-0020"},
+		{name: "Refresh", method: http.MethodPost, target: "/internal/license?action=refresh", token: "support", expectedStatus: http.StatusAccepted},
+		{name: "Invalidate", method: http.MethodPost, target: "/internal/license?action=invalidate&organizationId=org-a", token: "support", expectedStatus: http.StatusOK},
+		{name: "Invalidate unknown organization", method: http.MethodPost, target: "/internal/license?action=invalidate&organizationId=org-z", token: "support", expectedStatus: http.StatusBadRequest, expectedCode: "LCS-0011"},
+		{name: "Invalidate without organization", method: http.MethodPost, target: "/internal/license?action=invalidate", token: "support", expectedStatus: http.StatusBadRequest, expectedCode: "LCS-0021"},
+		{name: "Unknown action", method: http.MethodPost, target: "/internal/license?action=reboot", token: "support", expectedStatus: http.StatusBadRequest, expectedCode: "LCS-0021"},
+		{name: "Unsupported method", method: http.MethodDelete, target: "/internal/license", token: "support", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for name, serve := range servers {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, tt.target, nil)
+				if tt.token != "" {
+					req.Header.Set("Authorization", "Bearer "+tt.token)
+				}
+
+				resp := serve(req)
+				defer resp.Body.Close()
+
+				require.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+				switch {
+				case tt.expectedCode != "":
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+					assert.Equal(t, tt.expectedCode, body["code"])
+				case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted:
+					assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+					var status model.ClientStatus
+					require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+					assert.Equal(t, model.ModeMultiOrganization, status.Mode)
+					require.Len(t, status.Organizations, 2)
+					assert.Equal(t, "org-a", status.Organizations[0].OrganizationID)
+				}
+			})
+		}
+	}
+}
+
+func TestAdminHandler_Actions(t *testing.T) {
+	ts := orgLicenseServer(t, nil, map[string]model.ValidationResult{"org-a": {Valid: true, ExpiryDaysLeft: 90}})
+	defer ts.Close()
+
+	client, _ := newMetricsTestClient(t, ts, "org-a")
+	require.NoError(t, client.Start(context.Background()))
+
+	handler := client.HTTPAdminHandler(func(r *http.Request) error { return nil })
+
+	post := func(target string, expectedStatus int) model.ClientStatus {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, nil))
+		require.Equal(t, expectedStatus, rec.Code, rec.Body.String())
+
+		var status model.ClientStatus
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+
+		return status
+	}
+
+	check := func() {
+		_, err := client.Guard().Check(context.Background(), func() (string, error) { return "org-a", nil })
+		require.NoError(t, err)
+	}
+
+	// A cached request marks the result as served from the cache
+	check()
+	assert.Equal(t, model.SourceCache, client.Status().Organizations[0].Source)
+
+	// Once invalidated, the next request goes to the license server
+	post("/?action="+middleware.AdminActionInvalidate+"&organizationId=org-a", http.StatusOK)
+	check()
+	assert.Equal(t, model.SourceAPI, client.Status().Organizations[0].Source)
+
+	// The refresh runs in the background
+	post("/?action="+middleware.AdminActionRefresh, http.StatusAccepted)
+
+	require.Eventually(t, func() bool {
+		return !client.Status().LastSuccessfulRefresh.IsZero()
+	}, time.Second, 5*time.Millisecond)
+
+	status := client.Status()
+	assert.False(t, status.LastRefreshAttempt.IsZero())
+	assert.Equal(t, model.StateActive, status.Organizations[0].State)
+}
+
+func TestAdminHandler_RefreshNeverTerminates(t *testing.T) {
+	ts := orgLicenseServer(t, nil, nil)
+	defer ts.Close()
+
+	p := &scriptedProvider{result: model.ValidationResult{Valid: true, ExpiryDaysLeft: 90}}
+
+	client, _ := newMetricsTestClient(t, ts, "org-a", validation.WithLicenseProvider(p))
+	require.NoError(t, client.Start(context.Background()))
+
+	var terminations atomic.Int32
+
+	client.SetTerminationHandler(func(reason string) { terminations.Add(1) })
+
+	// A refresh rejecting every organization would terminate the application if it were scheduled
+	p.answer(model.ValidationResult{}, pkg.ForbiddenError{Code: "LIC-0002", Message: "license revoked"})
+
+	rec := httptest.NewRecorder()
+	client.HTTPAdminHandler(func(r *http.Request) error { return nil }).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/?action=refresh", nil))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	require.Eventually(t, func() bool {
+		return client.State("org-a") == model.StateRevoked
+	}, time.Second, 5*time.Millisecond)
+
+	// Let the refresh finish
+	require.Eventually(t, client.TriggerRefresh, time.Second, 5*time.Millisecond)
+
+	assert.Zero(t, terminations.Load())
+	assert.True(t, client.Status().LastSuccessfulRefresh.IsZero())
+}
+
+func TestAdminHandler_UninitializedClient(t *testing.T) {
+	var l log.Logger = testlogger.New()
+
+	// An invalid configuration makes NewLicenseClient return nil
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, "org-a", &l, validation.WithLicenseFile("license.jws"))
+	require.Nil(t, client)
+
+	for _, c := range []*middleware.LicenseClient{client, {}} {
+		assert.NotPanics(t, func() {
+			_ = c.GetLogger()
+		})
+
+		rec := httptest.NewRecorder()
+		c.HTTPAdminHandler(func(r *http.Request) error { return nil }).
+			ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.Equal(t, cn.ErrInternalServer.Error(), body["code"])
+	}
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowLicenseServer answers after the delay returned for the organization of each request,
+// recording the highest number of requests in flight
+func slowLicenseServer(t *testing.T, delay func(orgID string) time.Duration, maxInFlight *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	var inFlight atomic.Int32
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			highest := maxInFlight.Load()
+			if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		select {
+		case <-time.After(delay(body["organizationId"])):
+		case <-r.Context().Done():
+			return
+		}
+
+		JSONResponse(t, http.StatusOK, ValidationResult(true, 90))(w, r)
+	}))
+}
+
+func TestConcurrency_BoundedParallelism(t *testing.T) {
+	var maxInFlight atomic.Int32
+
+	ts := slowLicenseServer(t, func(string) time.Duration { return 100 * time.Millisecond }, &maxInFlight)
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "org-1,org-2,org-3,org-4,org-5,org-6", &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithConcurrency(3),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	report, err := client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+
+	// Two waves of three requests instead of six sequential round-trips
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, int32(3), maxInFlight.Load())
+	// Results keep the configuration order
+	assert.Equal(t, []string{"org-1", "org-2", "org-3", "org-4", "org-5", "org-6"}, report.ValidOrganizationIDs())
+}
+
+func TestConcurrency_OrganizationTimeout(t *testing.T) {
+	var maxInFlight atomic.Int32
+
+	ts := slowLicenseServer(t, func(orgID string) time.Duration {
+		if orgID == "org-slow" {
+			return time.Second
+		}
+
+		return 0
+	}, &maxInFlight)
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "org-slow,org-fast", &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithOrganizationTimeout(100*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	report, err := client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Organizations, 2)
+	assert.Equal(t, "org-slow", report.Organizations[0].OrganizationID)
+	assert.ErrorIs(t, report.Organizations[0].Err, cn.ErrOrgLicenseValidationFail)
+	assert.True(t, report.Organizations[1].Valid())
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/test/conformance"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFrameworkConformance runs the conformance scenarios against the framework adapters of this module.
+// The Gin and Echo adapters are separate modules and run the same scenarios in their own tests.
+func TestFrameworkConformance(t *testing.T) {
+	conformance.Run(t,
+		conformance.Adapter{
+			Name: "fiber",
+			Build: func(t *testing.T, client *middleware.LicenseClient, seen *conformance.Seen) func(req *http.Request) *http.Response {
+				app := fiber.New()
+				app.Get("/", client.Middleware(), func(c *fiber.Ctx) error {
+					seen.Store = c.Locals(middleware.OrganizationIDKey)
+					seen.Context, _ = middleware.OrganizationIDFromContext(c.UserContext())
+					seen.StoreDecision = c.Locals(middleware.DecisionKey)
+					seen.Decision, _ = middleware.FromContext(c.UserContext())
+
+					return c.SendString("success")
+				})
+
+				return func(req *http.Request) *http.Response {
+					resp, err := app.Test(req)
+					require.NoError(t, err)
+
+					return resp
+				}
+			},
+		},
+		conformance.Adapter{
+			Name: "net/http",
+			Build: func(t *testing.T, client *middleware.LicenseClient, seen *conformance.Seen) func(req *http.Request) *http.Response {
+				return conformance.Record(client.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					// net/http has no per-request store besides the context
+					if orgID, ok := middleware.OrganizationIDFromContext(r.Context()); ok {
+						seen.Store, seen.Context = orgID, orgID
+					}
+
+					if decision, ok := middleware.FromContext(r.Context()); ok {
+						seen.StoreDecision, seen.Decision = decision, decision
+					}
+
+					_, _ = io.WriteString(w, "success")
+				})))
+			},
+		},
+	)
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// decisionProvider reports a trial license for org-a and a license in its grace period for org-b
+type decisionProvider struct{}
+
+func (decisionProvider) Validate(ctx context.Context, orgID string) (model.ValidationResult, error) {
+	if orgID == "org-b" {
+		return model.ValidationResult{Valid: false, ActiveGracePeriod: true, ExpiryDaysLeft: -3}, nil
+	}
+
+	return model.ValidationResult{Valid: true, IsTrial: true, ExpiryDaysLeft: 5}, nil
+}
+
+func newDecisionTestClient(t *testing.T) *middleware.LicenseClient {
+	t.Helper()
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, "org-a,org-b", &l,
+		validation.WithLicenseProvider(decisionProvider{}),
+	)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	require.NoError(t, client.Start(context.Background()))
+
+	return client
+}
+
+func TestDecision_UnaryInterceptor(t *testing.T) {
+	client := newDecisionTestClient(t)
+
+	var decision middleware.Decision
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(cn.OrganizationIDHeader, "org-b"))
+	_, err := client.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		func(ctx context.Context, req any) (any, error) {
+			var ok bool
+			decision, ok = middleware.FromContext(ctx)
+			assert.True(t, ok)
+
+			return "response", nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, "org-b", decision.OrganizationID)
+	assert.True(t, decision.Result.ActiveGracePeriod)
+	assert.Equal(t, -3, decision.Result.ExpiryDaysLeft)
+}
+
+func TestDecision_StreamInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+
+	t.Run("Organization from metadata", func(t *testing.T) {
+		client := newDecisionTestClient(t)
+
+		var decision middleware.Decision
+
+		err := client.StreamServerInterceptor()(nil, newOrgStream(), info, func(srv any, ss grpc.ServerStream) error {
+			decision, _ = middleware.FromContext(ss.Context())
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "org-a", decision.OrganizationID)
+		assert.True(t, decision.Result.IsTrial)
+		assert.Equal(t, 5, decision.Result.ExpiryDaysLeft)
+	})
+
+	t.Run("Organization from messages", func(t *testing.T) {
+		client := newDecisionTestClient(t)
+		client.SetGRPCOrgResolver(middleware.MessageResolver(""))
+
+		var seen []string
+
+		stream := &messageStream{ctx: context.Background(), orgIDs: []string{"org-a", "org-b"}}
+
+		err := client.StreamServerInterceptor()(nil, stream, info, func(srv any, ss grpc.ServerStream) error {
+			// No message has carried the organization ID yet
+			_, ok := middleware.FromContext(ss.Context())
+			assert.False(t, ok)
+
+			for {
+				var msg orgMessage
+				if err := ss.RecvMsg(&msg); err == io.EOF {
+					return nil
+				} else if err != nil {
+					return err
+				}
+
+				decision, ok := middleware.FromContext(ss.Context())
+				require.True(t, ok)
+
+				seen = append(seen, decision.OrganizationID)
+			}
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"org-a", "org-b"}, seen)
+	})
+}
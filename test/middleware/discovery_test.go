@@ -0,0 +1,292 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/provider"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// discoveryServer serves the entitled organizations and validates every organization
+type discoveryServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	orgs []string
+}
+
+func newDiscoveryServer(t *testing.T, orgs ...string) *discoveryServer {
+	t.Helper()
+
+	s := &discoveryServer{orgs: orgs}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/licenses/organizations":
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			JSONResponse(t, http.StatusOK, model.OrganizationsResponse{OrganizationIDs: s.orgs})(w, r)
+		default:
+			JSONResponse(t, http.StatusOK, ValidationResult(true, 90))(w, r)
+		}
+	}))
+
+	return s
+}
+
+func (s *discoveryServer) setOrganizations(orgs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orgs = orgs
+}
+
+func TestDiscovery_RefreshesOrganizations(t *testing.T) {
+	ts := newDiscoveryServer(t, "org-a", "org-b")
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "", &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithOrganizationDiscovery(),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, client.GetOrganizationIDs())
+
+	var events []model.OrganizationEvent
+
+	client.SetOrganizationEventHandler(func(event model.OrganizationEvent) {
+		events = append(events, event)
+	})
+
+	report, err := client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org-a", "org-b"}, report.ValidOrganizationIDs())
+	assert.Equal(t, []string{"org-a", "org-b"}, client.GetOrganizationIDs())
+
+	// The next background validation picks up the new entitlements
+	ts.setOrganizations("org-b", "org-c")
+
+	require.NoError(t, client.ValidateWithRetry(context.Background()))
+	assert.Equal(t, []string{"org-b", "org-c"}, client.GetOrganizationIDs())
+
+	require.Len(t, events, 4)
+	assert.Equal(t, model.OrganizationEvent{Type: model.OrganizationAdded, OrganizationID: "org-c"}, events[2])
+	assert.Equal(t, model.OrganizationEvent{Type: model.OrganizationRemoved, OrganizationID: "org-a"}, events[3])
+}
+
+func TestDiscovery_KeepsRuntimeOrganizations(t *testing.T) {
+	ts := newDiscoveryServer(t, "org-a")
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "", &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithOrganizationDiscovery(),
+	)
+	require.NoError(t, err)
+
+	_, err = client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, client.AddOrganization(context.Background(), "org-z"))
+
+	// Organizations added at runtime survive the next discovery until they are removed
+	require.NoError(t, client.ValidateWithRetry(context.Background()))
+	assert.Equal(t, []string{"org-a", "org-z"}, client.GetOrganizationIDs())
+
+	require.NoError(t, client.RemoveOrganization("org-z"))
+	require.NoError(t, client.ValidateWithRetry(context.Background()))
+	assert.Equal(t, []string{"org-a"}, client.GetOrganizationIDs())
+}
+
+func TestDiscovery_ConcurrentRuntimeOrganizations(t *testing.T) {
+	ts := newDiscoveryServer(t, "org-a")
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "", &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithOrganizationDiscovery(),
+	)
+	require.NoError(t, err)
+
+	_, err = client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+
+	var (
+		mu      sync.Mutex
+		removed []string
+	)
+
+	client.SetOrganizationEventHandler(func(event model.OrganizationEvent) {
+		if event.Type == model.OrganizationRemoved {
+			mu.Lock()
+			removed = append(removed, event.OrganizationID)
+			mu.Unlock()
+		}
+	})
+
+	orgIDs := make([]string, 0, 50)
+	for i := range cap(orgIDs) {
+		orgIDs = append(orgIDs, fmt.Sprintf("org-%d", i))
+	}
+
+	var (
+		discovery, adds sync.WaitGroup
+		done            = make(chan struct{})
+	)
+
+	discovery.Add(1)
+
+	// Discovery runs continuously while the organizations are added
+	go func() {
+		defer discovery.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				assert.NoError(t, client.ValidateWithRetry(context.Background()))
+			}
+		}
+	}()
+
+	for _, orgID := range orgIDs {
+		adds.Add(1)
+
+		go func() {
+			defer adds.Done()
+
+			assert.NoError(t, client.AddOrganization(context.Background(), orgID))
+		}()
+	}
+
+	adds.Wait()
+	close(done)
+	discovery.Wait()
+
+	// An organization added while discovery runs is neither dropped nor reported as removed
+	assert.Empty(t, removed)
+	assert.ElementsMatch(t, append([]string{"org-a"}, orgIDs...), client.GetOrganizationIDs())
+}
+
+func TestDiscovery_FilteredByConfiguredOrganizations(t *testing.T) {
+	ts := newDiscoveryServer(t, "org-a", "org-b", "org-c")
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "org-c,org-a,org-x", &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithOrganizationDiscovery(),
+	)
+	require.NoError(t, err)
+
+	_, err = client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org-a", "org-c"}, client.GetOrganizationIDs())
+}
+
+func TestDiscovery_Failures(t *testing.T) {
+	var l log.Logger = testlogger.New()
+
+	t.Run("Provider without discovery", func(t *testing.T) {
+		_, err := validation.New(testAppID, testLicenseKey, "", &l,
+			validation.WithLicenseProvider(provider.NewDev()),
+			validation.WithOrganizationDiscovery(),
+		)
+		require.EqualError(t, err, "license provider does not support organization discovery")
+	})
+
+	t.Run("Global plugin mode", func(t *testing.T) {
+		_, err := validation.New(testAppID, testLicenseKey, cn.GlobalPluginValue, &l, validation.WithOrganizationDiscovery())
+		require.EqualError(t, err, "organization discovery cannot be used in global plugin mode")
+	})
+
+	t.Run("License server unavailable at startup", func(t *testing.T) {
+		ts := httptest.NewServer(JSONResponse(t, http.StatusServiceUnavailable, nil))
+		defer ts.Close()
+
+		client, err := validation.New(testAppID, testLicenseKey, "", &l,
+			validation.WithLicenseURL(ts.URL),
+			validation.WithOrganizationDiscovery(),
+		)
+		require.NoError(t, err)
+
+		_, err = client.ValidateStartup(context.Background())
+		require.ErrorIs(t, err, cn.ErrNoOrganizationIDs)
+	})
+
+	t.Run("Configured organizations served while discovery fails", func(t *testing.T) {
+		ts := httptest.NewServer(JSONResponse(t, http.StatusServiceUnavailable, nil))
+		defer ts.Close()
+
+		client, err := validation.New(testAppID, testLicenseKey, "org-a,org-b", &l,
+			validation.WithLicenseURL(ts.URL),
+			validation.WithOrganizationDiscovery(),
+		)
+		require.NoError(t, err)
+
+		report, err := client.ValidateStartup(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"org-a", "org-b"}, report.ValidOrganizationIDs())
+		assert.Equal(t, []string{"org-a", "org-b"}, client.GetOrganizationIDs())
+	})
+
+	t.Run("Snapshot organizations served while discovery fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "license-snapshot.json")
+
+		healthy := newDiscoveryServer(t, "org-a", "org-b")
+		defer healthy.Close()
+
+		client, err := validation.New(testAppID, testLicenseKey, "", &l,
+			validation.WithLicenseURL(healthy.URL),
+			validation.WithOrganizationDiscovery(),
+			validation.WithSnapshot(path, 24*time.Hour),
+		)
+		require.NoError(t, err)
+
+		_, err = client.ValidateStartup(context.Background())
+		require.NoError(t, err)
+
+		// After a restart the license server is down
+		ts := httptest.NewServer(JSONResponse(t, http.StatusServiceUnavailable, nil))
+		defer ts.Close()
+
+		client, err = validation.New(testAppID, testLicenseKey, "", &l,
+			validation.WithLicenseURL(ts.URL),
+			validation.WithOrganizationDiscovery(),
+			validation.WithSnapshot(path, 24*time.Hour),
+		)
+		require.NoError(t, err)
+
+		report, err := client.ValidateStartup(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"org-a", "org-b"}, report.ValidOrganizationIDs())
+	})
+
+	t.Run("Enabled from the environment", func(t *testing.T) {
+		t.Setenv(cn.EnvOrganizationDiscovery, "true")
+
+		_, err := validation.New(testAppID, testLicenseKey, "", &l, validation.WithLicenseProvider(provider.NewDev()))
+		require.EqualError(t, err, "license provider does not support organization discovery")
+	})
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointResolution(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		opts        []validation.Option
+		expectedURL string
+		expectedErr string
+	}{
+		{
+			name:        "Defaults to production",
+			expectedURL: cn.ProdLicenseGatewayBaseURL,
+		},
+		{
+			name:        "IS_DEVELOPMENT selects development",
+			env:         map[string]string{cn.EnvIsDevelopment: "true"},
+			expectedURL: cn.DevLicenseGatewayBaseURL,
+		},
+		{
+			name:        "LICENSE_PROFILE selects staging",
+			env:         map[string]string{cn.EnvLicenseProfile: "staging", cn.EnvIsDevelopment: "true"},
+			expectedURL: cn.StagingLicenseGatewayBaseURL,
+		},
+		{
+			name:        "LICENSE_URL overrides profiles",
+			env:         map[string]string{cn.EnvLicenseURL: "https://license.internal.example.com/", cn.EnvLicenseProfile: "dev"},
+			expectedURL: "https://license.internal.example.com",
+		},
+		{
+			name:        "Explicit URL overrides environment",
+			env:         map[string]string{cn.EnvLicenseURL: "https://license.internal.example.com"},
+			opts:        []validation.Option{validation.WithLicenseURL("http://gateway.local:8080")},
+			expectedURL: "http://gateway.local:8080",
+		},
+		{
+			name:        "Explicit profile overrides environment",
+			env:         map[string]string{cn.EnvLicenseURL: "https://license.internal.example.com"},
+			opts:        []validation.Option{validation.WithLicenseProfile(cn.ProfileDev)},
+			expectedURL: cn.DevLicenseGatewayBaseURL,
+		},
+		{
+			name:        "Custom profile without LICENSE_URL",
+			opts:        []validation.Option{validation.WithLicenseProfile(cn.ProfileCustom)},
+			expectedErr: `license profile "custom" requires LICENSE_URL to be set`,
+		},
+		{
+			name:        "Unknown profile",
+			env:         map[string]string{cn.EnvLicenseProfile: "qa"},
+			expectedErr: `unknown license profile "qa"`,
+		},
+		{
+			name:        "Invalid scheme",
+			opts:        []validation.Option{validation.WithLicenseURL("ftp://license.example.com")},
+			expectedErr: `invalid license server URL "ftp://license.example.com": scheme must be http or https`,
+		},
+		{
+			name:        "Missing host",
+			opts:        []validation.Option{validation.WithLicenseURL("https:///licenses")},
+			expectedErr: `invalid license server URL "https:///licenses": host is required`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{cn.EnvLicenseURL, cn.EnvLicenseProfile, cn.EnvIsDevelopment} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			var l log.Logger = testlogger.New()
+
+			client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l, tt.opts...)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedURL, client.GetBaseURL())
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestExemptions_HTTP(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	custom := middleware.Exemptions{
+		Paths:         []string{"/public/**", "/v1/*/status"},
+		Methods:       []string{http.MethodHead},
+		RequiredPaths: []string{"/public/billing/**"},
+	}
+
+	tests := []struct {
+		name           string
+		exemptions     *middleware.Exemptions
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "Default health check", method: http.MethodGet, path: "/health", expectedStatus: http.StatusOK},
+		{name: "Default nested health check", method: http.MethodGet, path: "/health/live", expectedStatus: http.StatusOK},
+		{name: "Default metrics", method: http.MethodGet, path: "/metrics", expectedStatus: http.StatusOK},
+		{name: "Default CORS preflight", method: http.MethodOptions, path: "/v1/items", expectedStatus: http.StatusOK},
+		{name: "Default business route", method: http.MethodGet, path: "/v1/items", expectedStatus: http.StatusBadRequest},
+		{name: "Custom prefix", exemptions: &custom, method: http.MethodGet, path: "/public/docs/index", expectedStatus: http.StatusOK},
+		{name: "Custom single segment", exemptions: &custom, method: http.MethodGet, path: "/v1/items/status", expectedStatus: http.StatusOK},
+		{name: "Custom method", exemptions: &custom, method: http.MethodHead, path: "/v1/items", expectedStatus: http.StatusOK},
+		{name: "Required wins over skip", exemptions: &custom, method: http.MethodGet, path: "/public/billing/plan", expectedStatus: http.StatusBadRequest},
+		{name: "Required wins over method", exemptions: &custom, method: http.MethodHead, path: "/public/billing", expectedStatus: http.StatusBadRequest},
+		{name: "Custom rules replace defaults", exemptions: &custom, method: http.MethodGet, path: "/health", expectedStatus: http.StatusBadRequest},
+		{name: "Nothing exempt", exemptions: &middleware.Exemptions{}, method: http.MethodOptions, path: "/v1/items", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStartupTestClient(t, ts, "org-a")
+			if tt.exemptions != nil {
+				require.NoError(t, client.SetExemptions(*tt.exemptions))
+			}
+
+			app := fiber.New()
+			app.Use(client.Middleware())
+			app.All("/*", func(c *fiber.Ctx) error {
+				return c.SendString("success")
+			})
+
+			handler := client.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "success")
+			}))
+
+			// No organization header is sent, so checked requests fail with LCS-0010
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode, "fiber")
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.expectedStatus, rec.Code, "net/http")
+		})
+	}
+}
+
+func TestExemptions_GRPC(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		exemptions   *middleware.Exemptions
+		method       string
+		expectedCode codes.Code
+	}{
+		{name: "Default health service", method: "/grpc.health.v1.Health/Check", expectedCode: codes.OK},
+		{name: "Default reflection service", method: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", expectedCode: codes.OK},
+		{name: "Default business method", method: "/ledger.v1.Ledger/Transfer", expectedCode: codes.InvalidArgument},
+		{
+			name: "Custom method",
+			exemptions: &middleware.Exemptions{
+				GRPCMethods:         []string{"/ledger.v1.Ledger/*"},
+				RequiredGRPCMethods: []string{"/ledger.v1.Ledger/Transfer"},
+			},
+			method:       "/ledger.v1.Ledger/Ping",
+			expectedCode: codes.OK,
+		},
+		{
+			name: "Required wins over skip",
+			exemptions: &middleware.Exemptions{
+				GRPCMethods:         []string{"/ledger.v1.Ledger/*"},
+				RequiredGRPCMethods: []string{"/ledger.v1.Ledger/Transfer"},
+			},
+			method:       "/ledger.v1.Ledger/Transfer",
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStartupTestClient(t, ts, "org-a")
+			if tt.exemptions != nil {
+				require.NoError(t, client.SetExemptions(*tt.exemptions))
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+
+			_, err := client.UnaryServerInterceptor()(ctx, "request", &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					return "response", nil
+				})
+			assert.Equal(t, tt.expectedCode, status.Code(err), "unary")
+
+			err = client.StreamServerInterceptor()(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(srv any, ss grpc.ServerStream) error {
+					return nil
+				})
+			assert.Equal(t, tt.expectedCode, status.Code(err), "stream")
+		})
+	}
+}
+
+func TestSetExemptions_InvalidPattern(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, "org-a")
+	assert.Error(t, client.SetExemptions(middleware.Exemptions{Paths: []string{"/v1/[items"}}))
+	assert.NoError(t, client.SetExemptions(middleware.Exemptions{Paths: []string{"/v1/items/**"}}))
+}
@@ -8,9 +8,9 @@ import (
 	"time"
 
 	cn "github.com/LerianStudio/lib-license-go/constant"
-	"github.com/LerianStudio/lib-license-go/internal/api"
 	"github.com/LerianStudio/lib-license-go/middleware"
 	"github.com/LerianStudio/lib-license-go/test/helper"
+	"github.com/LerianStudio/lib-license-go/validation"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -47,9 +47,6 @@ func TestGlobalPluginMode(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	// Override the license API URL for testing
-	api.SetTestLicenseBaseURL(ts.URL)
-
 	// Setup mock logger
 	mockLogger := helper.NewMockLogger()
 	mockLoggerImpl := helper.AsMock(mockLogger)
@@ -68,6 +65,7 @@ func TestGlobalPluginMode(t *testing.T) {
 				"test-license-key",
 				cn.GlobalPluginValue,
 				mockLogger,
+				validation.WithLicenseURL(ts.URL),
 			)
 
 			// Create a custom HTTP client that points to our test server
@@ -86,6 +84,7 @@ func TestGlobalPluginMode(t *testing.T) {
 			"test-license-key",
 			cn.GlobalPluginValue,
 			mockLogger,
+			validation.WithLicenseURL(ts.URL),
 		)
 
 		// Create a custom HTTP client that points to our test server
@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// captureOutgoing runs a unary client interceptor and returns the outgoing metadata it produced
+func captureOutgoing(t *testing.T, interceptor grpc.UnaryClientInterceptor, ctx context.Context) metadata.MD {
+	t.Helper()
+
+	var md metadata.MD
+
+	err := interceptor(ctx, "/test.Service/Method", "request", nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+	require.NoError(t, err)
+
+	return md
+}
+
+func TestUnaryClientInterceptor_OrganizationID(t *testing.T) {
+	interceptor := middleware.UnaryClientInterceptor()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected []string
+	}{
+		{
+			name:     "From WithOrganizationID",
+			ctx:      middleware.WithOrganizationID(context.Background(), "org-a"),
+			expected: []string{"org-a"},
+		},
+		{
+			name:     "From the incoming call",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs(cn.OrganizationIDHeader, "org-b")),
+			expected: []string{"org-b"},
+		},
+		{
+			name: "Explicit outgoing metadata wins",
+			ctx: metadata.AppendToOutgoingContext(
+				middleware.WithOrganizationID(context.Background(), "org-a"), cn.OrganizationIDHeader, "org-c"),
+			expected: []string{"org-c"},
+		},
+		{
+			name: "Without organization",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := captureOutgoing(t, interceptor, tt.ctx)
+			assert.Equal(t, tt.expected, md.Get(cn.OrganizationIDHeader))
+			assert.Empty(t, md.Get("authorization"))
+		})
+	}
+}
+
+func TestClientInterceptors_SignedTokenAcceptedByServer(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	opt, err := middleware.WithClientToken(middleware.ClientTokenConfig{
+		Key:      priv,
+		KeyID:    "svc-1",
+		Audience: "ledger",
+	})
+	require.NoError(t, err)
+
+	unary := middleware.UnaryClientInterceptor(opt)
+
+	ctx := middleware.WithOrganizationID(context.Background(), "org-a")
+
+	md := captureOutgoing(t, unary, ctx)
+	require.Len(t, md.Get("authorization"), 1)
+
+	// Tokens are reused while fresh
+	assert.Equal(t, md.Get("authorization"), captureOutgoing(t, unary, ctx).Get("authorization"))
+
+	// A call that already carries credentials keeps them
+	authorized := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer user-token")
+	assert.Equal(t, []string{"Bearer user-token"}, captureOutgoing(t, unary, authorized).Get("authorization"))
+
+	resolver, err := middleware.NewJWTResolver(middleware.JWTResolverConfig{
+		Keys:     map[string]crypto.PublicKey{"svc-1": pub},
+		Audience: "ledger",
+	})
+	require.NoError(t, err)
+
+	client := newStartupTestClient(t, ts, "org-a")
+	require.NoError(t, client.Start(context.Background()))
+	client.SetGRPCOrgResolver(resolver)
+
+	server := client.UnaryServerInterceptor()
+
+	resp, err := server(metadata.NewIncomingContext(context.Background(), md), "request",
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		func(ctx context.Context, req any) (any, error) {
+			return "response", nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "response", resp)
+
+	// The stream interceptor attaches the same metadata
+	stream := middleware.StreamClientInterceptor(opt)
+
+	var streamMD metadata.MD
+
+	_, err = stream(ctx, &grpc.StreamDesc{}, nil, "/test.Service/Stream",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			streamMD, _ = metadata.FromOutgoingContext(ctx)
+			return nil, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org-a"}, streamMD.Get(cn.OrganizationIDHeader))
+	assert.Len(t, streamMD.Get("authorization"), 1)
+}
+
+func TestClientInterceptors_TokenOnlyForTrustedOrganization(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	opt, err := middleware.WithClientToken(middleware.ClientTokenConfig{Key: priv, KeyID: "svc-1"})
+	require.NoError(t, err)
+
+	unary := middleware.UnaryClientInterceptor(opt)
+
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs(cn.OrganizationIDHeader, "org-x"))
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		expectedOrgID string
+		signed        bool
+	}{
+		{
+			name:          "Accepted by the license middleware",
+			ctx:           middleware.ContextWithDecision(incoming, middleware.Decision{OrganizationID: "org-a"}),
+			expectedOrgID: "org-a",
+			signed:        true,
+		},
+		{
+			name:          "Forwarded from the incoming call",
+			ctx:           incoming,
+			expectedOrgID: "org-x",
+		},
+		{
+			name:          "Outgoing metadata differs from the trusted organization",
+			ctx:           metadata.AppendToOutgoingContext(middleware.WithOrganizationID(context.Background(), "org-a"), cn.OrganizationIDHeader, "org-x"),
+			expectedOrgID: "org-x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := captureOutgoing(t, unary, tt.ctx)
+			assert.Equal(t, []string{tt.expectedOrgID}, md.Get(cn.OrganizationIDHeader))
+
+			if tt.signed {
+				assert.Len(t, md.Get("authorization"), 1)
+			} else {
+				assert.Empty(t, md.Get("authorization"))
+			}
+		})
+	}
+}
+
+func TestWithClientToken_InvalidKey(t *testing.T) {
+	// Only P-256 ECDSA keys can sign tokens
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		key      crypto.Signer
+		expected string
+	}{
+		{name: "Missing key", expected: "invalid client token key: signing key is required"},
+		{name: "Unsupported curve", key: p384, expected: "invalid client token key: unsupported ECDSA curve P-384"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, err := middleware.WithClientToken(middleware.ClientTokenConfig{Key: tt.key, KeyID: "svc-1"})
+			require.EqualError(t, err, tt.expected)
+			assert.Nil(t, opt)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+)
+
+// orgMessage is a request message exposing the organization ID through a getter
+type orgMessage struct {
+	OrganizationID string
+}
+
+func (m *orgMessage) GetOrganizationId() string { return m.OrganizationID }
+
+// messageStream is a client stream delivering orgMessages with the given organization IDs
+type messageStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	orgIDs []string
+	sent   int
+}
+
+func (s *messageStream) Context() context.Context { return s.ctx }
+func (s *messageStream) SendMsg(m any) error      { s.sent++; return nil }
+
+func (s *messageStream) RecvMsg(m any) error {
+	if len(s.orgIDs) == 0 {
+		return io.EOF
+	}
+
+	m.(*orgMessage).OrganizationID, s.orgIDs = s.orgIDs[0], s.orgIDs[1:]
+
+	return nil
+}
+
+func TestMessageResolver_Unary(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		resolver     middleware.GRPCOrgResolver
+		req          any
+		md           metadata.MD
+		expectedCode codes.Code
+	}{
+		{
+			name:         "Getter",
+			resolver:     middleware.MessageResolver(""),
+			req:          &orgMessage{OrganizationID: "org-a"},
+			md:           metadata.MD{},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Getter with unknown organization",
+			resolver:     middleware.MessageResolver(""),
+			req:          &orgMessage{OrganizationID: "org-x"},
+			md:           metadata.MD{},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Protobuf field path",
+			resolver:     middleware.MessageResolver("source_context.file_name"),
+			req:          &apipb.Api{SourceContext: &sourcecontextpb.SourceContext{FileName: "org-b"}},
+			md:           metadata.MD{},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Protobuf field path with JSON name",
+			resolver:     middleware.MessageResolver("sourceContext.fileName"),
+			req:          &apipb.Api{SourceContext: &sourcecontextpb.SourceContext{FileName: "org-b"}},
+			md:           metadata.MD{},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Protobuf field path not set",
+			resolver:     middleware.MessageResolver("source_context.file_name"),
+			req:          &apipb.Api{},
+			md:           metadata.MD{},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Unknown protobuf field",
+			resolver:     middleware.MessageResolver("tenant.organization_id"),
+			req:          &apipb.Api{},
+			md:           metadata.MD{},
+			expectedCode: codes.Internal,
+		},
+		{
+			name:         "Message before metadata",
+			resolver:     middleware.ChainGRPCResolvers(middleware.MessageResolver(""), middleware.MetadataResolver(cn.OrganizationIDHeader)),
+			req:          &orgMessage{},
+			md:           metadata.Pairs(cn.OrganizationIDHeader, "org-a"),
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Message conflicting with metadata",
+			resolver:     middleware.ChainGRPCResolvers(middleware.MessageResolver(""), middleware.MetadataResolver(cn.OrganizationIDHeader)),
+			req:          &orgMessage{OrganizationID: "org-b"},
+			md:           metadata.Pairs(cn.OrganizationIDHeader, "org-a"),
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	client := newStartupTestClient(t, ts, "org-a,org-b")
+	require.NoError(t, client.Start(context.Background()))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client.SetGRPCOrgResolver(tt.resolver)
+
+			_, err := client.UnaryServerInterceptor()(metadata.NewIncomingContext(context.Background(), tt.md), tt.req,
+				&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+				func(ctx context.Context, req any) (any, error) {
+					return "response", nil
+				})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestMessageResolver_ClientStream(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		md           metadata.MD
+		orgIDs       []string
+		received     int
+		expectedCode codes.Code
+		expectedLCS  string
+	}{
+		{name: "Every message valid", md: metadata.MD{}, orgIDs: []string{"org-a", "org-b", "org-a"}, received: 3, expectedCode: codes.OK},
+		{
+			name:         "Later message with unknown organization",
+			md:           metadata.MD{},
+			orgIDs:       []string{"org-a", "org-x"},
+			received:     1,
+			expectedCode: codes.InvalidArgument,
+			expectedLCS:  cn.ErrUnknownOrgIDHeader.Error(),
+		},
+		{
+			name:         "Messages without organization use the metadata",
+			md:           metadata.Pairs(cn.OrganizationIDHeader, "org-a"),
+			orgIDs:       []string{"", ""},
+			received:     2,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Message conflicting with metadata",
+			md:           metadata.Pairs(cn.OrganizationIDHeader, "org-a"),
+			orgIDs:       []string{"org-a", "org-b"},
+			received:     1,
+			expectedCode: codes.InvalidArgument,
+			expectedLCS:  cn.ErrConflictingOrgID.Error(),
+		},
+		{
+			name:         "No organization anywhere",
+			md:           metadata.MD{},
+			orgIDs:       []string{""},
+			expectedCode: codes.InvalidArgument,
+			expectedLCS:  cn.ErrMissingOrgIDHeader.Error(),
+		},
+	}
+
+	client := newStartupTestClient(t, ts, "org-a,org-b")
+	require.NoError(t, client.Start(context.Background()))
+	client.SetGRPCOrgResolver(middleware.ChainGRPCResolvers(
+		middleware.MessageResolver(""),
+		middleware.MetadataResolver(cn.OrganizationIDHeader),
+	))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &messageStream{ctx: metadata.NewIncomingContext(context.Background(), tt.md), orgIDs: tt.orgIDs}
+
+			var received int
+
+			err := client.StreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{FullMethod: "/test.Service/Upload"},
+				func(srv any, ss grpc.ServerStream) error {
+					for {
+						var msg orgMessage
+
+						err := ss.RecvMsg(&msg)
+						if err == io.EOF {
+							return nil
+						}
+
+						if err != nil {
+							return err
+						}
+
+						received++
+					}
+				})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.received, received)
+
+			if tt.expectedLCS != "" {
+				assert.Equal(t, tt.expectedLCS, status.Convert(err).Message())
+			}
+		})
+	}
+}
+
+func TestMessageResolver_SendBeforeOrganization(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, "org-a")
+	require.NoError(t, client.Start(context.Background()))
+	client.SetGRPCOrgResolver(middleware.MessageResolver(""))
+
+	stream := &messageStream{ctx: context.Background(), orgIDs: []string{"org-a"}}
+
+	err := client.StreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{FullMethod: "/test.Service/Chat"},
+		func(srv any, ss grpc.ServerStream) error {
+			// Nothing is sent before a message brings a validated organization
+			err := ss.SendMsg("greeting")
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.Equal(t, cn.ErrMissingOrgIDHeader.Error(), status.Convert(err).Message())
+
+			var msg orgMessage
+			require.NoError(t, ss.RecvMsg(&msg))
+
+			return ss.SendMsg("reply")
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stream.sent)
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// revocableProvider reports a valid license until it is revoked
+type revocableProvider struct {
+	revoked atomic.Bool
+}
+
+func (p *revocableProvider) Validate(ctx context.Context, orgID string) (model.ValidationResult, error) {
+	if p.revoked.Load() {
+		return model.ValidationResult{Valid: false}, nil
+	}
+
+	return model.ValidationResult{Valid: true, ExpiryDaysLeft: 90}, nil
+}
+
+// fakeServerStream is a server stream that delivers an empty message on every receive, or, when it has
+// a client channel, only when the client sends, like an idle gRPC stream
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	client chan any
+	sent   int
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+func (s *fakeServerStream) SendMsg(m any) error      { s.sent++; return nil }
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	if s.client == nil {
+		return nil
+	}
+
+	// Like the transport, a blocked receive only returns when the client sends or the call ends
+	select {
+	case <-s.client:
+		return nil
+	case <-s.ctx.Done():
+		return status.FromContextError(s.ctx.Err()).Err()
+	}
+}
+
+func newRevalidationTestClient(t *testing.T, p *revocableProvider, interval time.Duration, messages int) *middleware.LicenseClient {
+	t.Helper()
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, "org-a", &l,
+		validation.WithLicenseProvider(p),
+		validation.WithCacheTTL(time.Millisecond),
+	)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	require.NoError(t, client.Start(context.Background()))
+	client.SetStreamRevalidation(interval, messages)
+
+	return client
+}
+
+func newOrgStream() *fakeServerStream {
+	return &fakeServerStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(cn.OrganizationIDHeader, "org-a")),
+	}
+}
+
+func TestStreamRevalidation(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+
+	t.Run("Valid license keeps the stream open", func(t *testing.T) {
+		client := newRevalidationTestClient(t, &revocableProvider{}, 0, 2)
+		stream := newOrgStream()
+
+		err := client.StreamServerInterceptor()(nil, stream, info, func(srv any, ss grpc.ServerStream) error {
+			for range 5 {
+				if err := ss.SendMsg("message"); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, stream.sent)
+	})
+
+	t.Run("Revoked license ends the stream after the message threshold", func(t *testing.T) {
+		p := &revocableProvider{}
+		client := newRevalidationTestClient(t, p, 0, 2)
+
+		var received int
+
+		err := client.StreamServerInterceptor()(nil, newOrgStream(), info, func(srv any, ss grpc.ServerStream) error {
+			for {
+				if err := ss.RecvMsg(nil); err != nil {
+					return err
+				}
+
+				received++
+
+				if received == 1 {
+					p.revoked.Store(true)
+					// Let the cached valid result expire
+					time.Sleep(20 * time.Millisecond)
+				}
+			}
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), cn.ErrOrgLicenseInvalid.Error())
+		assert.Equal(t, 1, received)
+	})
+
+	t.Run("Revoked license ends an idle stream after the interval", func(t *testing.T) {
+		p := &revocableProvider{}
+		client := newRevalidationTestClient(t, p, 10*time.Millisecond, 0)
+
+		p.revoked.Store(true)
+
+		stream := newOrgStream()
+		stream.client = make(chan any)
+
+		// The gRPC server ends the transport stream once the interceptor returns
+		ctx, endCall := context.WithCancel(stream.ctx)
+		stream.ctx = ctx
+
+		done := make(chan error, 1)
+		handlerDone := make(chan error, 1)
+
+		go func() {
+			done <- client.StreamServerInterceptor()(nil, stream, info, func(srv any, ss grpc.ServerStream) error {
+				for {
+					if err := ss.RecvMsg(nil); err != nil {
+						handlerDone <- err
+						return err
+					}
+				}
+			})
+
+			endCall()
+		}()
+
+		select {
+		case err := <-done:
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+			assert.Contains(t, status.Convert(err).Message(), cn.ErrOrgLicenseInvalid.Error())
+		case <-time.After(2 * time.Second):
+			t.Fatal("stream was not terminated")
+		}
+
+		select {
+		case <-handlerDone:
+		case <-time.After(2 * time.Second):
+			t.Fatal("handler was not released when the call ended")
+		}
+	})
+}
+
+func TestStreamRevalidation_RefreshSeesRevocation(t *testing.T) {
+	p := &revocableProvider{}
+
+	var l log.Logger = testlogger.New()
+
+	// The cached valid result would outlive the stream if the refresh did not evict it
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, "org-a", &l,
+		validation.WithLicenseProvider(p),
+		validation.WithCacheTTL(time.Hour),
+	)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	require.NoError(t, client.Start(context.Background()))
+	client.SetStreamRevalidation(0, 1)
+	client.SetTerminationHandler(func(reason string) {})
+
+	var received int
+
+	err := client.StreamServerInterceptor()(nil, newOrgStream(), &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(srv any, ss grpc.ServerStream) error {
+			for {
+				if err := ss.RecvMsg(nil); err != nil {
+					return err
+				}
+
+				received++
+
+				switch received {
+				case 2:
+					p.revoked.Store(true)
+					_ = client.Refresh(context.Background())
+				case 10:
+					return nil
+				}
+			}
+		})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), cn.ErrOrgLicenseInvalid.Error())
+	assert.Equal(t, 2, received)
+	assert.Equal(t, model.StateExpired, client.State("org-a"))
+}
@@ -0,0 +1,404 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// testSigner signs test tokens with one key
+type testSigner struct {
+	alg string
+	kid string
+	key crypto.Signer
+	jwk map[string]string
+}
+
+func newRSASigner(t *testing.T, kid string) testSigner {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return testSigner{alg: "RS256", kid: kid, key: key, jwk: map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": b64(key.N.Bytes()),
+		"e": b64(big.NewInt(int64(key.E)).Bytes()),
+	}}
+}
+
+func newECSigner(t *testing.T, kid string) testSigner {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return testSigner{alg: "ES256", kid: kid, key: key, jwk: map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": b64(key.X.FillBytes(make([]byte, 32))),
+		"y": b64(key.Y.FillBytes(make([]byte, 32))),
+	}}
+}
+
+func newEdSigner(t *testing.T, kid string) testSigner {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return testSigner{alg: "EdDSA", kid: kid, key: priv, jwk: map[string]string{
+		"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(pub),
+	}}
+}
+
+// sign creates a compact JWS with the given claims
+func (s testSigner) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": s.alg, "kid": s.kid, "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := b64(header) + "." + b64(payload)
+
+	var signature []byte
+
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(input))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(input))
+
+		var r, sv *big.Int
+		r, sv, err = ecdsa.Sign(rand.Reader, key, digest[:])
+		signature = append(r.FillBytes(make([]byte, 32)), sv.FillBytes(make([]byte, 32))...)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(input))
+	}
+
+	require.NoError(t, err)
+
+	return input + "." + b64(signature)
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func jwksJSON(t *testing.T, signers ...testSigner) []byte {
+	t.Helper()
+
+	keys := make([]map[string]string, 0, len(signers))
+	for _, s := range signers {
+		keys = append(keys, s.jwk)
+	}
+
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+
+	return data
+}
+
+func orgClaims(orgID string) map[string]any {
+	return map[string]any{
+		"sub":             "user-1",
+		"iss":             "https://auth.example.com",
+		"aud":             []string{"ledger"},
+		"organization_id": orgID,
+		"exp":             time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTResolver_Fiber(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	rsaSigner := newRSASigner(t, "rsa-1")
+	ecSigner := newECSigner(t, "ec-1")
+	edSigner := newEdSigner(t, "ed-1")
+	untrusted := newEdSigner(t, "ed-1")
+
+	// The RSA and EC keys are served over HTTP, the Ed25519 key is read from a file
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jwksJSON(t, rsaSigner, ecSigner))
+	}))
+	defer jwks.Close()
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, jwksJSON(t, edSigner), 0o600))
+
+	resolver, err := middleware.NewJWTResolver(middleware.JWTResolverConfig{
+		JWKSURL:  jwks.URL,
+		JWKSFile: jwksFile,
+		Issuer:   "https://auth.example.com",
+		Audience: "ledger",
+	})
+	require.NoError(t, err)
+
+	expired := orgClaims("org-a")
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	otherAudience := orgClaims("org-a")
+	otherAudience["aud"] = "billing"
+
+	noClaim := orgClaims("org-a")
+	delete(noClaim, "organization_id")
+
+	noExpiry := orgClaims("org-a")
+	delete(noExpiry, "exp")
+
+	tests := []struct {
+		name           string
+		token          string
+		header         string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "RS256 token", token: rsaSigner.sign(t, orgClaims("org-a")), expectedStatus: http.StatusOK},
+		{name: "ES256 token", token: ecSigner.sign(t, orgClaims("org-b")), expectedStatus: http.StatusOK},
+		{name: "EdDSA token", token: edSigner.sign(t, orgClaims("org-a")), expectedStatus: http.StatusOK},
+		{name: "Matching header", token: rsaSigner.sign(t, orgClaims("org-a")), header: "org-a", expectedStatus: http.StatusOK},
+		{
+			name:           "Header naming another organization",
+			token:          rsaSigner.sign(t, orgClaims("org-a")),
+			header:         "org-b",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   cn.ErrOrgClaimMismatch.Error(),
+		},
+		{
+			name:           "Claim naming an unconfigured organization",
+			token:          ecSigner.sign(t, orgClaims("org-c")),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   cn.ErrUnknownOrgIDHeader.Error(),
+		},
+		{name: "Missing token", expectedStatus: http.StatusUnauthorized, expectedCode: cn.ErrInvalidOrgToken.Error()},
+		{
+			name:           "Untrusted signing key",
+			token:          untrusted.sign(t, orgClaims("org-a")),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   cn.ErrInvalidOrgToken.Error(),
+		},
+		{
+			name:           "Expired token",
+			token:          rsaSigner.sign(t, expired),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   cn.ErrInvalidOrgToken.Error(),
+		},
+		{
+			name:           "Token without expiry",
+			token:          rsaSigner.sign(t, noExpiry),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   cn.ErrInvalidOrgToken.Error(),
+		},
+		{
+			name:           "Wrong audience",
+			token:          rsaSigner.sign(t, otherAudience),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   cn.ErrInvalidOrgToken.Error(),
+		},
+		{
+			name:           "Token without organization claim",
+			token:          rsaSigner.sign(t, noClaim),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   cn.ErrInvalidOrgToken.Error(),
+		},
+	}
+
+	client := newStartupTestClient(t, ts, "org-a,org-b")
+	require.NoError(t, client.Start(context.Background()))
+	client.SetOrgResolver(resolver)
+
+	app := fiber.New()
+	app.Get("/", client.Middleware(), func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			if tt.header != "" {
+				req.Header.Set(cn.OrganizationIDHeader, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedCode != "" {
+				var body map[string]any
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.expectedCode, body["code"])
+			}
+		})
+	}
+}
+
+func TestJWTResolver_GRPC(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	signer := newRSASigner(t, "rsa-1")
+
+	resolver, err := middleware.NewJWTResolver(middleware.JWTResolverConfig{
+		Keys: map[string]crypto.PublicKey{"rsa-1": signer.key.Public()},
+	})
+	require.NoError(t, err)
+
+	client := newStartupTestClient(t, ts, "org-a,org-b")
+	require.NoError(t, client.Start(context.Background()))
+	client.SetGRPCOrgResolver(resolver)
+
+	interceptor := client.UnaryServerInterceptor()
+
+	tests := []struct {
+		name         string
+		md           metadata.MD
+		expectedCode codes.Code
+	}{
+		{name: "Valid token", md: metadata.Pairs("authorization", "Bearer "+signer.sign(t, orgClaims("org-a"))), expectedCode: codes.OK},
+		{name: "Missing token", md: metadata.Pairs(cn.OrganizationIDHeader, "org-a"), expectedCode: codes.Unauthenticated},
+		{
+			name:         "Metadata naming another organization",
+			md:           metadata.Pairs("authorization", "Bearer "+signer.sign(t, orgClaims("org-a")), cn.OrganizationIDHeader, "org-b"),
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "Claims swapped after signing",
+			md:           metadata.Pairs("authorization", "Bearer "+swapClaims(signer.sign(t, orgClaims("org-a")), signer.sign(t, orgClaims("org-b")))),
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			_, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+				func(ctx context.Context, req any) (any, error) {
+					return "response", nil
+				})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+// swapClaims returns token carrying the claims segment of other
+func swapClaims(token, other string) string {
+	parts := strings.Split(token, ".")
+	parts[1] = strings.Split(other, ".")[1]
+
+	return strings.Join(parts, ".")
+}
+
+func TestNewJWTResolver_RequiresKeys(t *testing.T) {
+	_, err := middleware.NewJWTResolver(middleware.JWTResolverConfig{})
+	require.Error(t, err)
+}
+
+func TestJWTResolver_AllowMissingExpiry(t *testing.T) {
+	signer := newEdSigner(t, "ed-1")
+
+	claims := orgClaims("org-a")
+	delete(claims, "exp")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signer.sign(t, claims))
+
+	strict, err := middleware.NewJWTResolver(middleware.JWTResolverConfig{
+		Keys: map[string]crypto.PublicKey{"ed-1": signer.key.Public()},
+	})
+	require.NoError(t, err)
+
+	_, err = strict.ResolveHTTP(req)
+	require.ErrorIs(t, err, cn.ErrInvalidOrgToken)
+
+	lenient, err := middleware.NewJWTResolver(middleware.JWTResolverConfig{
+		Keys:               map[string]crypto.PublicKey{"ed-1": signer.key.Public()},
+		AllowMissingExpiry: true,
+	})
+	require.NoError(t, err)
+
+	orgID, err := lenient.ResolveHTTP(req)
+	require.NoError(t, err)
+	assert.Equal(t, "org-a", orgID)
+}
+
+func TestJWTResolver_SharesJWKSDownload(t *testing.T) {
+	signer := newEdSigner(t, "ed-1")
+
+	var downloads atomic.Int32
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		time.Sleep(100 * time.Millisecond)
+
+		_, _ = w.Write(jwksJSON(t, signer))
+	}))
+	defer jwks.Close()
+
+	resolver, err := middleware.NewJWTResolver(middleware.JWTResolverConfig{JWKSURL: jwks.URL})
+	require.NoError(t, err)
+
+	token := signer.sign(t, orgClaims("org-a"))
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 10)
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			_, err := resolver.ResolveHTTP(req)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), downloads.Load(), "concurrent requests share one JWKS download")
+}
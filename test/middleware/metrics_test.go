@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// orgLicenseServer answers validations with the status code and result configured for each organization
+func orgLicenseServer(t *testing.T, statusCodes map[string]int, results map[string]model.ValidationResult) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		orgID := body["organizationId"]
+		if code, ok := statusCodes[orgID]; ok {
+			JSONResponse(t, code, map[string]string{"code": "LIC-0001", "message": "rejected"})(w, r)
+			return
+		}
+
+		JSONResponse(t, http.StatusOK, results[orgID])(w, r)
+	}))
+}
+
+// collectMetrics returns the metrics recorded so far, by name
+func collectMetrics(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	metrics := make(map[string]metricdata.Aggregation)
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	return metrics
+}
+
+// sumOf returns the value of the counter data point with the given attributes
+func sumOf(t *testing.T, data metricdata.Aggregation, attrs ...attribute.KeyValue) int64 {
+	t.Helper()
+
+	sum, ok := data.(metricdata.Sum[int64])
+	require.True(t, ok, "expected an int64 sum, got %T", data)
+
+	want := attribute.NewSet(attrs...)
+
+	for _, point := range sum.DataPoints {
+		if point.Attributes.Equals(&want) {
+			return point.Value
+		}
+	}
+
+	return 0
+}
+
+// gaugeOf returns the value of the gauge data point with the given attributes. A gauge without data
+// points is not collected at all, so a nil aggregation has none.
+func gaugeOf(t *testing.T, data metricdata.Aggregation, attrs ...attribute.KeyValue) (int64, bool) {
+	t.Helper()
+
+	if data == nil {
+		return 0, false
+	}
+
+	gauge, ok := data.(metricdata.Gauge[int64])
+	require.True(t, ok, "expected an int64 gauge, got %T", data)
+
+	want := attribute.NewSet(attrs...)
+
+	for _, point := range gauge.DataPoints {
+		if point.Attributes.Equals(&want) {
+			return point.Value, true
+		}
+	}
+
+	return 0, false
+}
+
+func newMetricsTestClient(t *testing.T, ts *httptest.Server, orgIDs string, opts ...validation.Option) (*middleware.LicenseClient, sdkmetric.Reader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+
+	var l log.Logger = testlogger.New()
+
+	opts = append([]validation.Option{
+		validation.WithLicenseURL(ts.URL),
+		validation.WithHTTPClient(newTestClient(ts)),
+		validation.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	}, opts...)
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, orgIDs, &l, opts...)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	return client, reader
+}
+
+func TestMetrics_Validations(t *testing.T) {
+	ts := orgLicenseServer(t, map[string]int{"org-c": http.StatusForbidden}, map[string]model.ValidationResult{
+		"org-a": {Valid: true, ExpiryDaysLeft: 20},
+		"org-b": {Valid: false, ActiveGracePeriod: true, ExpiryDaysLeft: 3},
+	})
+	defer ts.Close()
+
+	client, reader := newMetricsTestClient(t, ts, "org-a,org-b,org-c")
+	require.NoError(t, client.Start(context.Background()))
+
+	// Served from cache
+	_, err := client.Guard().Check(context.Background(), func() (string, error) { return "org-a", nil })
+	require.NoError(t, err)
+
+	metrics := collectMetrics(t, reader)
+
+	validations := metrics["license.validations"]
+	assert.Equal(t, int64(1), sumOf(t, validations, attribute.String("license.outcome", "valid")))
+	assert.Equal(t, int64(1), sumOf(t, validations, attribute.String("license.outcome", "grace_period")))
+	assert.Equal(t, int64(1), sumOf(t, validations,
+		attribute.String("license.outcome", "rejected"),
+		attribute.Int("http.response.status_code", http.StatusForbidden),
+	))
+
+	gateway, ok := metrics["license.gateway.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+
+	var requests uint64
+	for _, point := range gateway.DataPoints {
+		requests += point.Count
+	}
+
+	assert.Equal(t, uint64(3), requests)
+
+	assert.Positive(t, sumOf(t, metrics["license.cache.lookups"], attribute.String("license.cache.result", "hit")))
+
+	days, ok := gaugeOf(t, metrics["license.expiry.days"], attribute.String("license.organization.id", "org-a"))
+	assert.True(t, ok)
+	assert.Equal(t, int64(20), days)
+
+	grace, ok := gaugeOf(t, metrics["license.grace_period.active"], attribute.String("license.organization.id", "org-b"))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), grace)
+
+	_, ok = gaugeOf(t, metrics["license.expiry.days"], attribute.String("license.organization.id", "org-c"))
+	assert.False(t, ok, "rejected organizations have no expiry gauge")
+
+	// Removed organizations stop reporting
+	require.NoError(t, client.RemoveOrganization("org-b"))
+
+	_, ok = gaugeOf(t, collectMetrics(t, reader)["license.expiry.days"], attribute.String("license.organization.id", "org-b"))
+	assert.False(t, ok)
+}
+
+func TestMetrics_RevokedLicenseClearsGauges(t *testing.T) {
+	ts := orgLicenseServer(t, nil, nil)
+	defer ts.Close()
+
+	p := &scriptedProvider{result: model.ValidationResult{Valid: true, ExpiryDaysLeft: 90}}
+
+	client, reader := newMetricsTestClient(t, ts, "org-a", validation.WithLicenseProvider(p))
+	require.NoError(t, client.Start(context.Background()))
+	client.SetTerminationHandler(func(reason string) {})
+
+	org := attribute.String("license.organization.id", "org-a")
+
+	days, ok := gaugeOf(t, collectMetrics(t, reader)["license.expiry.days"], org)
+	require.True(t, ok)
+	assert.Equal(t, int64(90), days)
+
+	p.answer(model.ValidationResult{}, pkg.ForbiddenError{Code: "LIC-0002", Message: "license revoked"})
+	_ = client.Refresh(context.Background())
+
+	metrics := collectMetrics(t, reader)
+	assert.Equal(t, int64(1), sumOf(t, metrics["license.validations"], attribute.String("license.outcome", "rejected")))
+
+	_, ok = gaugeOf(t, metrics["license.expiry.days"], org)
+	assert.False(t, ok, "a revoked organization stops reporting its last valid expiry")
+
+	_, ok = gaugeOf(t, metrics["license.grace_period.active"], org)
+	assert.False(t, ok)
+}
+
+func TestMetrics_FallbackAndRefresh(t *testing.T) {
+	ts := orgLicenseServer(t, map[string]int{"org-a": http.StatusBadGateway}, nil)
+	defer ts.Close()
+
+	client, reader := newMetricsTestClient(t, ts, "org-a", validation.WithRefreshInterval(10*time.Millisecond))
+	require.NoError(t, client.Start(context.Background()))
+
+	metrics := collectMetrics(t, reader)
+	assert.Equal(t, int64(1), sumOf(t, metrics["license.fallbacks"], attribute.String("license.fallback.source", "grace_days")))
+	assert.Equal(t, int64(1), sumOf(t, metrics["license.validations"],
+		attribute.String("license.outcome", "fallback"),
+		attribute.Int("http.response.status_code", http.StatusBadGateway),
+	))
+
+	require.Eventually(t, func() bool {
+		refreshed, ok := collectMetrics(t, reader)["license.refresh.last_success"].(metricdata.Gauge[float64])
+		return ok && len(refreshed.DataPoints) == 1 && refreshed.DataPoints[0].Value > 0
+	}, time.Second, 10*time.Millisecond)
+}
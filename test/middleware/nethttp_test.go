@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTPMiddleware_MatchesFiber checks that the net/http middleware answers every request
+// with the same status code and body as the Fiber middleware
+func TestHTTPMiddleware_MatchesFiber(t *testing.T) {
+	validServer := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer validServer.Close()
+
+	invalidServer := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(false, 0)))
+	defer invalidServer.Close()
+
+	tests := []struct {
+		name           string
+		server         *httptest.Server
+		orgIDs         string
+		orgID          string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "Valid organization", server: validServer, orgIDs: "org-a,org-b", orgID: "org-b", expectedStatus: http.StatusOK},
+		{
+			name:           "Missing organization header",
+			server:         validServer,
+			orgIDs:         "org-a",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   cn.ErrMissingOrgIDHeader.Error(),
+		},
+		{
+			name:           "Unknown organization",
+			server:         validServer,
+			orgIDs:         "org-a",
+			orgID:          "org-x",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   cn.ErrUnknownOrgIDHeader.Error(),
+		},
+		{
+			name:           "Startup validation failed",
+			server:         invalidServer,
+			orgIDs:         "org-a",
+			orgID:          "org-a",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   cn.ErrNoValidLicenses.Error(),
+		},
+		{name: "Global mode", server: validServer, orgIDs: cn.GlobalPluginValue, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Both middlewares share the client, so startup validation runs once
+			client := newStartupTestClient(t, tt.server, tt.orgIDs)
+
+			app := fiber.New()
+			app.Get("/", client.Middleware(), func(c *fiber.Ctx) error {
+				return c.SendString("success")
+			})
+
+			handler := client.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "success")
+			}))
+
+			newRequest := func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if tt.orgID != "" {
+					req.Header.Set(cn.OrganizationIDHeader, tt.orgID)
+				}
+
+				return req
+			}
+
+			fiberResp, err := app.Test(newRequest())
+			require.NoError(t, err)
+
+			fiberBody, err := io.ReadAll(fiberResp.Body)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newRequest())
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, fiberResp.StatusCode, rec.Code)
+
+			if tt.expectedCode == "" {
+				assert.Equal(t, "success", rec.Body.String())
+				return
+			}
+
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, string(fiberBody), rec.Body.String())
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedCode, body["code"])
+		})
+	}
+}
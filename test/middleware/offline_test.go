@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/offline"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/provider"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const testKeyID = "test-key-2025"
+
+// writeLicenseFile signs the document and writes it to a temporary license file
+func writeLicenseFile(t *testing.T, doc offline.Document, key ed25519.PrivateKey) string {
+	t.Helper()
+
+	token, err := offline.Sign(doc, testKeyID, key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "license.jws")
+	require.NoError(t, os.WriteFile(path, token, 0o600))
+
+	return path
+}
+
+// newOfflineTestClient creates a license client validating against the given license file
+func newOfflineTestClient(t *testing.T, path, orgIDs string, pub ed25519.PublicKey) *middleware.LicenseClient {
+	t.Helper()
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, orgIDs, &l,
+		validation.WithLicenseFile(path),
+		validation.WithLicensePublicKey(testKeyID, pub),
+	)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	return client
+}
+
+func TestOfflineLicense_Middleware(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	path := writeLicenseFile(t, offline.Document{
+		AppName:         testAppID,
+		OrganizationIDs: []string{"org-a", "org-b"},
+		ExpiresAt:       time.Now().AddDate(0, 0, 45),
+	}, priv)
+
+	client := newOfflineTestClient(t, path, "org-a,org-c", pub)
+	require.NoError(t, client.Start(context.Background()))
+
+	app := fiber.New()
+	app.Use(client.Middleware())
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	tests := []struct {
+		orgID          string
+		expectedStatus int
+	}{
+		{orgID: "org-a", expectedStatus: http.StatusOK},
+		// Configured but not covered by the license file
+		{orgID: "org-c", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(cn.OrganizationIDHeader, tt.orgID)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expectedStatus, resp.StatusCode, "org %s", tt.orgID)
+	}
+}
+
+func TestOfflineLicense_UnaryInterceptor(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	// Expired two days ago with a ten day grace period
+	path := writeLicenseFile(t, offline.Document{
+		AppName:         testAppID,
+		OrganizationIDs: []string{testOrgID},
+		ExpiresAt:       time.Now().AddDate(0, 0, -2),
+		GracePeriodDays: 10,
+	}, priv)
+
+	client := newOfflineTestClient(t, path, testOrgID, pub)
+	interceptor := client.UnaryServerInterceptor()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(cn.OrganizationIDHeader, testOrgID))
+
+	resp, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		func(ctx context.Context, req any) (any, error) {
+			return "response", nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "response", resp)
+}
+
+func TestOfflineLicense_RejectedFiles(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	validDoc := offline.Document{
+		AppName:         testAppID,
+		OrganizationIDs: []string{testOrgID},
+		ExpiresAt:       time.Now().AddDate(1, 0, 0),
+	}
+
+	otherAppDoc := validDoc
+	otherAppDoc.AppName = "other-app"
+
+	expiredDoc := validDoc
+	expiredDoc.ExpiresAt = time.Now().AddDate(0, 0, -30)
+	expiredDoc.GracePeriodDays = 15
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "Signed by an untrusted key", path: writeLicenseFile(t, validDoc, otherPriv)},
+		{name: "Issued for another application", path: writeLicenseFile(t, otherAppDoc, priv)},
+		{name: "Expired after grace period", path: writeLicenseFile(t, expiredDoc, priv)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newOfflineTestClient(t, tt.path, testOrgID, pub)
+
+			err := client.Start(context.Background())
+			require.ErrorIs(t, err, cn.ErrNoValidLicenses)
+		})
+	}
+}
+
+func TestOfflineLicense_RequiresPublicKey(t *testing.T) {
+	var l log.Logger = testlogger.New()
+
+	_, err := validation.New(testAppID, testLicenseKey, testOrgID, &l, validation.WithLicenseFile("license.jws"))
+	require.EqualError(t, err, "at least one public key is required to verify the license file")
+}
+
+func TestOfflineLicense_RejectsMalformedPublicKey(t *testing.T) {
+	var l log.Logger = testlogger.New()
+
+	_, err := validation.New(testAppID, testLicenseKey, testOrgID, &l,
+		validation.WithLicenseFile("license.jws"),
+		validation.WithLicensePublicKey("short", ed25519.PublicKey([]byte("not-a-key"))),
+	)
+	require.EqualError(t, err, `public key "short" must be 32 bytes, got 9`)
+
+	_, err = provider.NewFile(testAppID, "license.jws", map[string]ed25519.PublicKey{"short": []byte("not-a-key")}, &l)
+	require.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_InvalidConfiguration(t *testing.T) {
+	tests := []struct {
+		name        string
+		opt         validation.Option
+		expectedErr string
+	}{
+		{name: "Zero HTTP timeout", opt: validation.WithHTTPTimeout(0), expectedErr: "HTTP timeout must be positive"},
+		{name: "Negative refresh interval", opt: validation.WithRefreshInterval(-time.Hour), expectedErr: "refresh interval must be positive"},
+		{name: "No retries", opt: validation.WithRetryPolicy(0, time.Second), expectedErr: "max retries must be at least 1"},
+		{name: "Negative backoff", opt: validation.WithRetryPolicy(3, -time.Second), expectedErr: "retry backoff must not be negative"},
+		{name: "Zero cache TTL", opt: validation.WithCacheTTL(0), expectedErr: "cache TTL must be positive"},
+		{name: "Negative fallback days", opt: validation.WithFallbackGraceDays(-1), expectedErr: "fallback grace days must not be negative"},
+		{name: "No concurrency", opt: validation.WithConcurrency(0), expectedErr: "max concurrency must be at least 1"},
+		{name: "Negative organization timeout", opt: validation.WithOrganizationTimeout(-time.Second), expectedErr: "organization timeout must be positive"},
+		{name: "Zero snapshot max age", opt: validation.WithSnapshot("snapshot.json", 0), expectedErr: "snapshot max age must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l log.Logger = testlogger.New()
+
+			_, err := validation.New(testAppID, testLicenseKey, testOrgID, &l, tt.opt)
+			require.EqualError(t, err, tt.expectedErr)
+
+			assert.Nil(t, middleware.NewLicenseClient(testAppID, testLicenseKey, testOrgID, &l, tt.opt))
+		})
+	}
+}
+
+func TestOptions_FallbackGraceDaysAndHTTPClient(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusBadGateway, nil))
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithHTTPClient(newTestClient(ts)),
+		validation.WithFallbackGraceDays(3),
+	)
+	require.NoError(t, err)
+
+	report, err := client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Organizations, 1)
+	assert.True(t, report.Organizations[0].Fallback)
+	assert.Equal(t, 3, report.Organizations[0].Result.ExpiryDaysLeft)
+}
+
+func TestOptions_RetryPolicy(t *testing.T) {
+	var attempts atomic.Int32
+
+	// Drop every connection so each attempt fails with a connection error
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+
+		_ = conn.Close()
+	}))
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithHTTPClient(newTestClient(ts)),
+		validation.WithRetryPolicy(2, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	require.Error(t, client.ValidateWithRetry(context.Background()))
+	assert.Equal(t, int32(2), attempts.Load())
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// perOrgLicenseServer answers valid for every organization except the rejected one
+func perOrgLicenseServer(t *testing.T, rejected string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		if body["organizationId"] == rejected {
+			JSONResponse(t, http.StatusForbidden, map[string]any{
+				"code":    "INVALID_LICENSE",
+				"message": "organization not covered by the license",
+			})(w, r)
+
+			return
+		}
+
+		JSONResponse(t, http.StatusOK, ValidationResult(true, 90))(w, r)
+	}))
+}
+
+func TestOrganizations_AddAndRemove(t *testing.T) {
+	ts := perOrgLicenseServer(t, "org-rejected")
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, testOrgID)
+	require.NoError(t, client.Start(context.Background()))
+
+	var (
+		mu     sync.Mutex
+		events []model.OrganizationEvent
+	)
+
+	client.SetOrganizationEventHandler(func(event model.OrganizationEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, event)
+	})
+
+	app := fiber.New()
+	app.Use(client.Middleware())
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	status := func(orgID string) int {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(cn.OrganizationIDHeader, orgID)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		return resp.StatusCode
+	}
+
+	assert.NotEqual(t, http.StatusOK, status("org-new"))
+
+	require.NoError(t, client.AddOrganization(context.Background(), "org-new"))
+	// Adding twice is a no-op
+	require.NoError(t, client.AddOrganization(context.Background(), "org-new"))
+	assert.Equal(t, []string{testOrgID, "org-new"}, client.Organizations())
+	assert.Equal(t, http.StatusOK, status("org-new"))
+
+	err := client.AddOrganization(context.Background(), "org-rejected")
+	require.Error(t, err)
+	assert.NotContains(t, client.Organizations(), "org-rejected")
+
+	require.NoError(t, client.RemoveOrganization("org-new"))
+	assert.Equal(t, []string{testOrgID}, client.Organizations())
+	assert.NotEqual(t, http.StatusOK, status("org-new"))
+
+	require.Error(t, client.RemoveOrganization("org-new"))
+	require.EqualError(t, client.RemoveOrganization(testOrgID), "at least one organization ID is required")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, events, 3)
+	assert.Equal(t, model.OrganizationAdded, events[0].Type)
+	assert.Equal(t, "org-new", events[0].OrganizationID)
+	assert.True(t, events[0].Result.Valid)
+	assert.Equal(t, model.OrganizationRejected, events[1].Type)
+	assert.Error(t, events[1].Err)
+	assert.Equal(t, model.OrganizationRemoved, events[2].Type)
+}
+
+func TestOrganizations_ConcurrentWithRequestPath(t *testing.T) {
+	ts := perOrgLicenseServer(t, "")
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, testOrgID)
+	require.NoError(t, client.Start(context.Background()))
+
+	app := fiber.New()
+	app.Use(client.Middleware())
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		orgID := fmt.Sprintf("org-%d", i)
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, client.AddOrganization(context.Background(), orgID))
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(cn.OrganizationIDHeader, testOrgID)
+
+			resp, err := app.Test(req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, client.Organizations(), 11)
+}
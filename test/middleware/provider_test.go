@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+	"github.com/LerianStudio/lib-license-go/provider"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider is a batch provider recording how it was called
+type countingProvider struct {
+	*provider.Static
+	mu          sync.Mutex
+	batchCalls  [][]string
+	singleCalls []string
+}
+
+func (p *countingProvider) Validate(ctx context.Context, orgID string) (model.ValidationResult, error) {
+	p.mu.Lock()
+	p.singleCalls = append(p.singleCalls, orgID)
+	p.mu.Unlock()
+
+	return p.Static.Validate(ctx, orgID)
+}
+
+func (p *countingProvider) ValidateBatch(ctx context.Context, orgIDs []string) (map[string]model.ValidationResult, error) {
+	p.mu.Lock()
+	p.batchCalls = append(p.batchCalls, orgIDs)
+	p.mu.Unlock()
+
+	return p.Static.ValidateBatch(ctx, orgIDs)
+}
+
+func TestProvider_StaticMiddleware(t *testing.T) {
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, "org-a,org-b", &l,
+		validation.WithLicenseProvider(provider.NewStatic(map[string]model.ValidationResult{
+			"org-a": {Valid: true, ExpiryDaysLeft: 90},
+			"org-b": {Valid: false},
+		})),
+	)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	require.NoError(t, client.Start(context.Background()))
+
+	app := fiber.New()
+	app.Use(client.Middleware())
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	tests := []struct {
+		orgID          string
+		expectedStatus int
+	}{
+		{orgID: "org-a", expectedStatus: http.StatusOK},
+		// An expired license is rejected as a business validation error
+		{orgID: "org-b", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(cn.OrganizationIDHeader, tt.orgID)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expectedStatus, resp.StatusCode, "org %s", tt.orgID)
+	}
+}
+
+func TestProvider_Dev(t *testing.T) {
+	result, err := provider.NewDev().Validate(context.Background(), "any-org")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	_, err = provider.NewStatic(nil).Validate(context.Background(), "any-org")
+	require.Error(t, err)
+}
+
+func TestProvider_ChainFailsOver(t *testing.T) {
+	// The primary license server is unreachable
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 30)))
+	ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	primary, err := provider.NewHTTP(testAppID, testLicenseKey, ts.URL, nil, &l)
+	require.NoError(t, err)
+
+	secondary := provider.NewStatic(map[string]model.ValidationResult{
+		testOrgID: {Valid: true, ExpiryDaysLeft: 12},
+	})
+
+	client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l,
+		validation.WithLicenseProvider(provider.NewChain(primary, secondary)),
+	)
+	require.NoError(t, err)
+
+	report, err := client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Organizations, 1)
+	assert.Equal(t, 12, report.Organizations[0].Result.ExpiryDaysLeft)
+}
+
+func TestProvider_ChainReturnsPrimaryError(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusServiceUnavailable, nil))
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	primary, err := provider.NewHTTP(testAppID, testLicenseKey, ts.URL, nil, &l)
+	require.NoError(t, err)
+
+	// The secondary license server is unreachable too
+	down := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 30)))
+	down.Close()
+
+	secondary, err := provider.NewHTTP(testAppID, testLicenseKey, down.URL, nil, &l)
+	require.NoError(t, err)
+
+	client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l,
+		validation.WithLicenseProvider(provider.NewChain(primary, secondary)),
+	)
+	require.NoError(t, err)
+
+	// The 5xx answer of the primary provider still triggers the fallback result
+	report, err := client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Organizations, 1)
+	assert.True(t, report.Organizations[0].Fallback)
+}
+
+func TestProvider_ChainDoesNotFailOverRejections(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusForbidden, map[string]any{
+		"code":    "LICENSE_REVOKED",
+		"message": "license revoked",
+	}))
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	primary, err := provider.NewHTTP(testAppID, testLicenseKey, ts.URL, nil, &l)
+	require.NoError(t, err)
+
+	fallback := &countingProvider{Static: provider.NewDev()}
+
+	client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l,
+		validation.WithLicenseProvider(provider.NewChain(primary, fallback)),
+	)
+	require.NoError(t, err)
+
+	// A revoked license stays revoked: the fallback is not consulted
+	report, err := client.ValidateStartup(context.Background())
+	require.ErrorIs(t, err, cn.ErrNoValidLicenses)
+	require.Len(t, report.Organizations, 1)
+
+	var forbiddenErr pkg.ForbiddenError
+	assert.ErrorAs(t, report.Organizations[0].Err, &forbiddenErr)
+	assert.Empty(t, fallback.singleCalls)
+}
+
+func TestProvider_Batch(t *testing.T) {
+	p := &countingProvider{Static: provider.NewStatic(map[string]model.ValidationResult{
+		"org-a": {Valid: true, ExpiryDaysLeft: 90},
+		"org-b": {Valid: true, ExpiryDaysLeft: 60},
+	})}
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "org-a,org-b,org-c", &l, validation.WithLicenseProvider(p))
+	require.NoError(t, err)
+
+	report, err := client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"org-a", "org-b", "org-c"}}, p.batchCalls)
+	// Only the organization missing from the batch results is validated on its own
+	assert.Equal(t, []string{"org-c"}, p.singleCalls)
+	assert.Equal(t, []string{"org-a", "org-b"}, report.ValidOrganizationIDs())
+	assert.Equal(t, []string{"org-c"}, report.FailedOrganizationIDs())
+}
+
+func TestProvider_FileRequiresPublicKey(t *testing.T) {
+	_, err := provider.NewFile(testAppID, "license.jws", nil, nil)
+	require.EqualError(t, err, "at least one public key is required to verify the license file")
+}
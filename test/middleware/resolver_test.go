@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgResolvers(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		resolver       middleware.OrgResolver
+		prepare        func(req *http.Request)
+		path           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Path parameter",
+			resolver:       middleware.PathParamResolver("organization_id"),
+			path:           "/v1/organizations/org-a/items",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Path parameter ignores the header",
+			resolver:       middleware.PathParamResolver("organization_id"),
+			prepare:        func(req *http.Request) { req.Header.Set(cn.OrganizationIDHeader, "org-unknown") },
+			path:           "/v1/organizations/org-b/items",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Query string",
+			resolver:       middleware.QueryResolver("org"),
+			path:           "/v1/organizations/x/items?org=org-a",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Subdomain",
+			resolver:       middleware.SubdomainResolver("api.example.com"),
+			prepare:        func(req *http.Request) { req.Host = "org-b.api.example.com:8443" },
+			path:           "/v1/organizations/x/items",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Locals set by authentication",
+			resolver:       middleware.LocalsResolver("tenant"),
+			prepare:        func(req *http.Request) { req.Header.Set("X-Test-Tenant", "org-a") },
+			path:           "/v1/organizations/x/items",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing from every source",
+			resolver:       middleware.ChainResolvers(middleware.HeaderResolver(cn.OrganizationIDHeader), middleware.QueryResolver("org")),
+			path:           "/v1/organizations/x/items",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   cn.ErrMissingOrgIDHeader.Error(),
+		},
+		{
+			name: "Chain with agreeing sources",
+			resolver: middleware.ChainResolvers(
+				middleware.HeaderResolver(cn.OrganizationIDHeader),
+				middleware.PathParamResolver("organization_id"),
+			),
+			prepare:        func(req *http.Request) { req.Header.Set(cn.OrganizationIDHeader, "org-a") },
+			path:           "/v1/organizations/org-a/items",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Chain with conflicting sources",
+			resolver: middleware.ChainResolvers(
+				middleware.HeaderResolver(cn.OrganizationIDHeader),
+				middleware.PathParamResolver("organization_id"),
+			),
+			prepare:        func(req *http.Request) { req.Header.Set(cn.OrganizationIDHeader, "org-a") },
+			path:           "/v1/organizations/org-b/items",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   cn.ErrConflictingOrgID.Error(),
+		},
+		{
+			name: "Custom function",
+			resolver: middleware.OrgResolverFunc(func(ctx *fiber.Ctx) (string, error) {
+				return "org-b", nil
+			}),
+			path:           "/v1/organizations/x/items",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStartupTestClient(t, ts, "org-a,org-b")
+			require.NoError(t, client.Start(context.Background()))
+			client.SetOrgResolver(tt.resolver)
+
+			app := fiber.New()
+			// Stands in for an authentication middleware storing the tenant
+			app.Use(func(c *fiber.Ctx) error {
+				if tenant := c.Get("X-Test-Tenant"); tenant != "" {
+					c.Locals("tenant", tenant)
+				}
+
+				return c.Next()
+			})
+			app.Get("/v1/organizations/:organization_id/items", client.Middleware(), func(c *fiber.Ctx) error {
+				return c.SendString("success")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.prepare != nil {
+				tt.prepare(req)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedCode != "" {
+				var body map[string]any
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.expectedCode, body["code"])
+			}
+		})
+	}
+}
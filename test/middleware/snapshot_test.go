@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/snapshot"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSnapshot validates against a healthy license server so the result is persisted to a new snapshot file
+func writeSnapshot(t *testing.T) string {
+	t.Helper()
+
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 30)))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "license-snapshot.json")
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithSnapshot(path, 24*time.Hour),
+	)
+	require.NoError(t, err)
+
+	_, err = client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+	require.FileExists(t, path)
+
+	return path
+}
+
+// validateOffline validates against an unreachable license server using the given snapshot
+func validateOffline(t *testing.T, licenseKey, path string, maxAge time.Duration) (validation.Report, error) {
+	t.Helper()
+
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 30)))
+	ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, licenseKey, testOrgID, &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithSnapshot(path, maxAge),
+	)
+	require.NoError(t, err)
+
+	return client.ValidateStartup(context.Background())
+}
+
+func TestSnapshot_UsedAfterRestartWithoutNetwork(t *testing.T) {
+	path := writeSnapshot(t)
+
+	report, err := validateOffline(t, testLicenseKey, path, 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, report.Organizations, 1)
+	assert.True(t, report.Organizations[0].Result.Valid)
+	assert.Equal(t, 30, report.Organizations[0].Result.ExpiryDaysLeft)
+	assert.False(t, report.Organizations[0].Fallback)
+}
+
+func TestSnapshot_Rejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		licenseKey string
+		maxAge     time.Duration
+		tamper     bool
+	}{
+		{name: "Tampered file", licenseKey: testLicenseKey, maxAge: 24 * time.Hour, tamper: true},
+		{name: "Different license key", licenseKey: "other-license-key", maxAge: 24 * time.Hour},
+		{name: "Outside the offline window", licenseKey: testLicenseKey, maxAge: time.Nanosecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeSnapshot(t)
+
+			if tt.tamper {
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+
+				tampered := strings.Replace(string(data), `"expiryDaysLeft":30`, `"expiryDaysLeft":3000`, 1)
+				require.NotEqual(t, string(data), tampered)
+				require.NoError(t, os.WriteFile(path, []byte(tampered), 0o600))
+			}
+
+			_, err := validateOffline(t, tt.licenseKey, path, tt.maxAge)
+			require.ErrorIs(t, err, cn.ErrNoValidLicenses)
+		})
+	}
+}
+
+func TestSnapshot_WrittenOncePerBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "license-snapshot.json")
+
+	var l log.Logger = testlogger.New()
+
+	store := snapshot.New(path, testAppID, testLicenseKey, l)
+
+	for _, orgID := range []string{"org-a", "org-b", "org-c"} {
+		store.Record(orgID, model.ValidationResult{Valid: true, ExpiryDaysLeft: 30}, time.Now())
+	}
+
+	assert.NoFileExists(t, path, "results are only written on flush")
+
+	require.NoError(t, store.Flush())
+	require.FileExists(t, path)
+
+	// Nothing was recorded since the last write
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, store.Flush())
+	assert.NoFileExists(t, path)
+
+	reloaded := snapshot.New(path, testAppID, testLicenseKey, l)
+	store.Record("org-a", model.ValidationResult{Valid: true, ExpiryDaysLeft: 29}, time.Now())
+	require.NoError(t, store.Flush())
+	require.NoError(t, reloaded.Load())
+
+	for _, orgID := range []string{"org-a", "org-b", "org-c"} {
+		_, _, found := reloaded.Lookup(orgID, time.Hour, time.Now())
+		assert.True(t, found, orgID)
+	}
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStartupTestClient creates a license client pointed at the given test server
+func newStartupTestClient(t *testing.T, ts *httptest.Server, orgIDs string) *middleware.LicenseClient {
+	t.Helper()
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, orgIDs, &l, validation.WithLicenseURL(ts.URL))
+	require.NotNil(t, client)
+
+	client.SetHTTPClient(newTestClient(ts))
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	return client
+}
+
+func TestStart_ValidLicense(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 60)))
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, testOrgID)
+
+	require.NoError(t, client.Start(context.Background()))
+	// A second call returns the same outcome without validating again
+	require.NoError(t, client.Start(context.Background()))
+}
+
+func TestStart_InvalidLicenseReturnsError(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusForbidden, map[string]any{
+		"code":    "INVALID_LICENSE",
+		"message": "invalid license",
+	}))
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, "org-a,org-b")
+
+	err := client.Start(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, cn.ErrNoValidLicenses)
+
+	var startupErr *validation.StartupError
+	require.True(t, errors.As(err, &startupErr))
+	assert.Equal(t, []string{"org-a", "org-b"}, startupErr.OrganizationIDs)
+	assert.Len(t, startupErr.Errors, 2)
+
+	// Building the middleware must not panic and every request is rejected with LCS-0003
+	var handler fiber.Handler
+
+	assert.NotPanics(t, func() {
+		handler = client.Middleware()
+	})
+
+	app := fiber.New()
+	app.Use(handler)
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(cn.OrganizationIDHeader, "org-a")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestStart_PanicOnFailureCompatibility(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusForbidden, map[string]any{
+		"code":    "INVALID_LICENSE",
+		"message": "invalid license",
+	}))
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, testOrgID)
+	client.SetPanicOnFailure(true)
+
+	assert.Panics(t, func() {
+		_ = client.Middleware()
+	})
+}
+
+func TestValidateStartup_Report(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusInternalServerError, nil))
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "org-a", &l, validation.WithLicenseURL(ts.URL))
+	require.NoError(t, err)
+
+	client.SetHTTPClient(newTestClient(ts))
+
+	report, err := client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Organizations, 1)
+
+	// Server errors fall back to a temporary valid result with an active grace period
+	org := report.Organizations[0]
+	assert.True(t, org.Valid())
+	assert.True(t, org.Fallback)
+	assert.Equal(t, cn.FallbackExpiryDaysLeft, org.Result.ExpiryDaysLeft)
+	assert.Equal(t, []string{"org-a"}, report.ValidOrganizationIDs())
+	assert.Empty(t, report.FailedOrganizationIDs())
+}
+
+func TestStart_RecoversAfterFailure(t *testing.T) {
+	var licensed atomic.Bool
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !licensed.Load() {
+			JSONResponse(t, http.StatusForbidden, map[string]any{"code": "INVALID_LICENSE", "message": "invalid license"})(w, r)
+			return
+		}
+
+		JSONResponse(t, http.StatusOK, ValidationResult(true, 60))(w, r)
+	}))
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, testOrgID, &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithHTTPClient(newTestClient(ts)),
+		validation.WithRetryPolicy(1, 5*time.Millisecond),
+	)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	require.Error(t, client.Ready(), "not ready before startup validation")
+
+	err := client.Start(context.Background())
+	require.ErrorIs(t, err, cn.ErrNoValidLicenses)
+	assert.ErrorIs(t, client.Ready(), cn.ErrNoValidLicenses)
+
+	app := fiber.New()
+	app.Get("/test", client.Middleware(), func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	request := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(cn.OrganizationIDHeader, testOrgID)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusBadRequest, request())
+
+	// The license is renewed: the retries clear the startup failure
+	licensed.Store(true)
+
+	require.Eventually(t, func() bool { return client.Ready() == nil }, time.Second, 5*time.Millisecond)
+	assert.NoError(t, client.Start(context.Background()))
+	assert.Equal(t, http.StatusOK, request())
+	require.Eventually(t, func() bool { return !client.Status().NextRefresh.IsZero() }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedProvider answers every validation with the current result or error
+type scriptedProvider struct {
+	mu     sync.Mutex
+	result model.ValidationResult
+	err    error
+}
+
+func (p *scriptedProvider) Validate(ctx context.Context, orgID string) (model.ValidationResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.result, p.err
+}
+
+func (p *scriptedProvider) answer(result model.ValidationResult, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.result, p.err = result, err
+}
+
+func TestLicenseStateMachine(t *testing.T) {
+	steps := []struct {
+		name     string
+		result   model.ValidationResult
+		err      error
+		expected model.LicenseState
+	}{
+		{name: "Active", result: model.ValidationResult{Valid: true, ExpiryDaysLeft: 90}, expected: model.StateActive},
+		{name: "Still active", result: model.ValidationResult{Valid: true, ExpiryDaysLeft: 89}, expected: model.StateActive},
+		{name: "Expiring", result: model.ValidationResult{Valid: true, ExpiryDaysLeft: 10}, expected: model.StateExpiring},
+		{name: "Trial", result: model.ValidationResult{Valid: true, IsTrial: true, ExpiryDaysLeft: 14}, expected: model.StateTrial},
+		{name: "Grace", result: model.ValidationResult{Valid: false, ActiveGracePeriod: true, ExpiryDaysLeft: 5}, expected: model.StateGrace},
+		{name: "Expired", result: model.ValidationResult{Valid: false}, expected: model.StateExpired},
+		{name: "Server error without cached result", err: &pkg.HTTPError{StatusCode: http.StatusBadGateway}, expected: model.StateOffline},
+		{name: "Provider failure", err: errors.New("license provider unavailable"), expected: model.StateOffline},
+		{name: "Revoked", err: pkg.ForbiddenError{Code: "LIC-0002", Message: "license revoked"}, expected: model.StateRevoked},
+	}
+
+	p := &scriptedProvider{}
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "org-a", &l,
+		validation.WithLicenseProvider(p),
+		validation.WithCacheTTL(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	var transitions, observed []model.StateTransition
+
+	client.OnStateChange(func(transition model.StateTransition) {
+		transitions = append(transitions, transition)
+	})
+	client.OnStateChange(func(transition model.StateTransition) {
+		observed = append(observed, transition)
+	})
+
+	assert.Equal(t, model.StateUnknown, client.State("org-a"))
+
+	for _, step := range steps {
+		p.answer(step.result, step.err)
+
+		// Let the previous result expire from the cache
+		time.Sleep(5 * time.Millisecond)
+
+		_, _ = client.ValidateOrganizationWithCache(context.Background(), "org-a")
+		assert.Equal(t, step.expected, client.State("org-a"), step.name)
+	}
+
+	expected := []struct{ from, to model.LicenseState }{
+		{model.StateUnknown, model.StateActive},
+		{model.StateActive, model.StateExpiring},
+		{model.StateExpiring, model.StateTrial},
+		{model.StateTrial, model.StateGrace},
+		{model.StateGrace, model.StateExpired},
+		{model.StateExpired, model.StateOffline},
+		{model.StateOffline, model.StateRevoked},
+	}
+
+	require.Len(t, transitions, len(expected))
+	assert.Equal(t, transitions, observed, "every subscriber sees every transition")
+
+	for i, want := range expected {
+		assert.Equal(t, "org-a", transitions[i].OrganizationID)
+		assert.Equal(t, want.from, transitions[i].From, "transition %d", i)
+		assert.Equal(t, want.to, transitions[i].To, "transition %d", i)
+		assert.False(t, transitions[i].At.IsZero())
+	}
+
+	assert.Equal(t, 5, transitions[3].Result.ExpiryDaysLeft)
+
+	var forbiddenErr pkg.ForbiddenError
+	assert.ErrorAs(t, transitions[6].Err, &forbiddenErr)
+}
+
+func TestLicenseState_RemovedOrganization(t *testing.T) {
+	p := &scriptedProvider{result: model.ValidationResult{Valid: true, ExpiryDaysLeft: 90}}
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "org-a,org-b", &l, validation.WithLicenseProvider(p))
+	require.NoError(t, err)
+
+	_, err = client.ValidateStartup(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, model.StateActive, client.State("org-b"))
+
+	require.NoError(t, client.RemoveOrganization("org-b"))
+	assert.Equal(t, model.StateUnknown, client.State("org-b"))
+	assert.Equal(t, model.StateActive, client.State("org-a"))
+}
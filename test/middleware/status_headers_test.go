@@ -0,0 +1,234 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/provider"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// headerTransportStream records the header metadata set by a unary interceptor
+type headerTransportStream struct {
+	header metadata.MD
+}
+
+func (s *headerTransportStream) Method() string { return "/test.Service/Method" }
+
+func (s *headerTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerTransportStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+func (s *headerTransportStream) SetTrailer(md metadata.MD) error { return nil }
+
+func newStatusHeadersTestClient(t *testing.T, orgIDs string, headers middleware.StatusHeaders) *middleware.LicenseClient {
+	t.Helper()
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, orgIDs, &l,
+		validation.WithLicenseProvider(provider.NewStatic(map[string]model.ValidationResult{
+			"org-active":         {Valid: true, ExpiryDaysLeft: 90},
+			"org-expiring":       {Valid: true, ExpiryDaysLeft: 20},
+			"org-trial":          {Valid: true, IsTrial: true, ExpiryDaysLeft: 10},
+			"org-trial-ending":   {Valid: true, IsTrial: true, ExpiryDaysLeft: 1},
+			"org-grace":          {Valid: false, ActiveGracePeriod: true, ExpiryDaysLeft: 5},
+			cn.GlobalPluginValue: {Valid: true, ExpiryDaysLeft: 3},
+		})),
+	)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	require.NoError(t, client.Start(context.Background()))
+	client.SetStatusHeaders(headers)
+
+	return client
+}
+
+func TestStatusHeaders(t *testing.T) {
+	const orgIDs = "org-active,org-expiring,org-trial,org-trial-ending,org-grace"
+
+	tests := []struct {
+		name     string
+		orgIDs   string
+		orgID    string
+		path     string
+		headers  middleware.StatusHeaders
+		expected map[string]string
+	}{
+		{
+			name:   "Active license",
+			orgIDs: orgIDs,
+			orgID:  "org-active",
+			expected: map[string]string{
+				"X-License-Status":          "active",
+				"X-License-Expires-In-Days": "90",
+				"X-License-Grace-Period":    "false",
+				"X-License-Trial":           "false",
+			},
+		},
+		{
+			name:     "Active license with warnings only",
+			orgIDs:   orgIDs,
+			orgID:    "org-active",
+			headers:  middleware.StatusHeaders{WarningsOnly: true},
+			expected: nil,
+		},
+		{
+			name:    "Expiring license with warnings only",
+			orgIDs:  orgIDs,
+			orgID:   "org-expiring",
+			headers: middleware.StatusHeaders{WarningsOnly: true},
+			expected: map[string]string{
+				"X-License-Status":          "expiring",
+				"X-License-Expires-In-Days": "20",
+				"X-License-Grace-Period":    "false",
+				"X-License-Trial":           "false",
+			},
+		},
+		{
+			name:     "Trial outside the warning threshold",
+			orgIDs:   orgIDs,
+			orgID:    "org-trial",
+			headers:  middleware.StatusHeaders{WarningsOnly: true},
+			expected: nil,
+		},
+		{
+			name:    "Trial ending with a custom prefix",
+			orgIDs:  orgIDs,
+			orgID:   "org-trial-ending",
+			headers: middleware.StatusHeaders{Prefix: "X-Acme-", WarningsOnly: true},
+			expected: map[string]string{
+				"X-Acme-Status":          "trial",
+				"X-Acme-Expires-In-Days": "1",
+				"X-Acme-Grace-Period":    "false",
+				"X-Acme-Trial":           "true",
+			},
+		},
+		{
+			name:    "Grace period",
+			orgIDs:  orgIDs,
+			orgID:   "org-grace",
+			headers: middleware.StatusHeaders{WarningsOnly: true},
+			expected: map[string]string{
+				"X-License-Status":          "grace",
+				"X-License-Expires-In-Days": "5",
+				"X-License-Grace-Period":    "true",
+				"X-License-Trial":           "false",
+			},
+		},
+		{
+			name:     "Exempt route",
+			orgIDs:   orgIDs,
+			path:     "/healthz",
+			expected: nil,
+		},
+		{
+			name:   "Global mode",
+			orgIDs: cn.GlobalPluginValue,
+			expected: map[string]string{
+				"X-License-Status":          "expiring",
+				"X-License-Expires-In-Days": "3",
+				"X-License-Grace-Period":    "false",
+				"X-License-Trial":           "false",
+			},
+		},
+	}
+
+	assertHeaders := func(t *testing.T, expected map[string]string, header func(name string) string) {
+		if expected == nil {
+			for _, name := range []string{"Status", "Expires-In-Days", "Grace-Period", "Trial"} {
+				assert.Empty(t, header("X-License-"+name))
+			}
+		}
+
+		for name, value := range expected {
+			assert.Equal(t, value, header(name), name)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStatusHeadersTestClient(t, tt.orgIDs, tt.headers)
+
+			path := tt.path
+			if path == "" {
+				path = "/"
+			}
+
+			newRequest := func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				if tt.orgID != "" {
+					req.Header.Set(cn.OrganizationIDHeader, tt.orgID)
+				}
+
+				return req
+			}
+
+			t.Run("fiber", func(t *testing.T) {
+				app := fiber.New()
+				app.Get(path, client.Middleware(), func(c *fiber.Ctx) error {
+					return c.SendString("success")
+				})
+
+				resp, err := app.Test(newRequest())
+				require.NoError(t, err)
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assertHeaders(t, tt.expected, resp.Header.Get)
+			})
+
+			t.Run("net/http", func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				client.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = io.WriteString(w, "success")
+				})).ServeHTTP(rec, newRequest())
+
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assertHeaders(t, tt.expected, rec.Header().Get)
+			})
+
+			if tt.path != "" {
+				return
+			}
+
+			t.Run("grpc", func(t *testing.T) {
+				stream := &headerTransportStream{}
+
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(cn.OrganizationIDHeader, tt.orgID))
+				ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+				_, err := client.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: stream.Method()},
+					func(ctx context.Context, req any) (any, error) {
+						return "response", nil
+					})
+				require.NoError(t, err)
+
+				assertHeaders(t, tt.expected, func(name string) string {
+					values := stream.header.Get(name)
+					if len(values) == 0 {
+						return ""
+					}
+
+					return values[0]
+				})
+			})
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/internal/offline"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/provider"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus_Organizations(t *testing.T) {
+	ts := orgLicenseServer(t,
+		map[string]int{"org-b": http.StatusBadGateway, "org-c": http.StatusForbidden},
+		map[string]model.ValidationResult{"org-a": {Valid: true, ExpiryDaysLeft: 20}},
+	)
+	defer ts.Close()
+
+	client, _ := newMetricsTestClient(t, ts, "org-a,org-b,org-c", validation.WithRefreshInterval(time.Hour))
+
+	status := client.Status()
+	assert.Equal(t, model.ModeMultiOrganization, status.Mode)
+	assert.Equal(t, ts.URL, status.Endpoint)
+	require.Len(t, status.Organizations, 3)
+	assert.Equal(t, model.StateUnknown, status.Organizations[0].State)
+	assert.Empty(t, status.Organizations[0].Source)
+	assert.True(t, status.NextRefresh.IsZero(), "no refresh is scheduled before the client starts")
+
+	started := time.Now()
+	require.NoError(t, client.Start(context.Background()))
+
+	// Served from cache
+	_, err := client.Guard().Check(context.Background(), func() (string, error) { return "org-a", nil })
+	require.NoError(t, err)
+
+	status = client.Status()
+	require.Len(t, status.Organizations, 3)
+
+	orgA := status.Organizations[0]
+	assert.Equal(t, "org-a", orgA.OrganizationID)
+	assert.Equal(t, model.StateExpiring, orgA.State)
+	assert.Equal(t, model.SourceCache, orgA.Source)
+	assert.Equal(t, 20, orgA.Result.ExpiryDaysLeft)
+	assert.False(t, orgA.ValidatedAt.Before(started))
+	assert.Empty(t, orgA.Error)
+
+	orgB := status.Organizations[1]
+	assert.Equal(t, model.StateOffline, orgB.State)
+	assert.Equal(t, model.SourceFallback, orgB.Source)
+	assert.True(t, orgB.Result.ActiveGracePeriod)
+
+	orgC := status.Organizations[2]
+	assert.Equal(t, model.StateRevoked, orgC.State)
+	assert.Equal(t, model.SourceAPI, orgC.Source)
+	assert.NotEmpty(t, orgC.Error)
+
+	// The background refresh starts asynchronously
+	require.Eventually(t, func() bool { return !client.Status().NextRefresh.IsZero() }, time.Second, time.Millisecond)
+	assert.WithinDuration(t, started.Add(time.Hour), client.Status().NextRefresh, time.Minute)
+	assert.True(t, status.LastRefreshAttempt.IsZero())
+	assert.True(t, status.LastSuccessfulRefresh.IsZero())
+
+	// The snapshot is a copy
+	status.Organizations[0].State = model.StateRevoked
+	assert.Equal(t, model.StateExpiring, client.Status().Organizations[0].State)
+
+	body, err := json.Marshal(client.Status())
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"mode":"multi-organization"`)
+	assert.Contains(t, string(body), `"organizationId":"org-b","state":"offline"`)
+}
+
+func TestStatus_RefreshAndGlobalMode(t *testing.T) {
+	ts := orgLicenseServer(t, nil, map[string]model.ValidationResult{cn.GlobalPluginValue: {Valid: true, ExpiryDaysLeft: 90}})
+	defer ts.Close()
+
+	client, _ := newMetricsTestClient(t, ts, cn.GlobalPluginValue, validation.WithRefreshInterval(10*time.Millisecond))
+	require.NoError(t, client.Start(context.Background()))
+
+	require.Eventually(t, func() bool {
+		return !client.Status().LastSuccessfulRefresh.IsZero()
+	}, time.Second, 10*time.Millisecond)
+
+	status := client.Status()
+	assert.Equal(t, model.ModeGlobal, status.Mode)
+	assert.False(t, status.LastRefreshAttempt.IsZero())
+	assert.False(t, status.NextRefresh.IsZero())
+
+	require.Len(t, status.Organizations, 1)
+	assert.Equal(t, cn.GlobalPluginValue, status.Organizations[0].OrganizationID)
+	assert.Equal(t, model.StateActive, status.Organizations[0].State)
+	assert.Equal(t, model.SourceAPI, status.Organizations[0].Source)
+
+	client.ShutdownBackgroundRefresh()
+	assert.True(t, client.Status().NextRefresh.IsZero())
+}
+
+func TestStatus_EndpointWithoutLicenseServer(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	path := writeLicenseFile(t, offline.Document{
+		AppName:         testAppID,
+		OrganizationIDs: []string{"org-a"},
+		ExpiresAt:       time.Now().AddDate(0, 0, 45),
+	}, priv)
+
+	assert.Empty(t, newOfflineTestClient(t, path, "org-a", pub).Status().Endpoint, "offline license file")
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, "org-a", &l, validation.WithLicenseProvider(provider.NewDev()))
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+
+	assert.Empty(t, client.Status().Endpoint, "custom provider")
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTermination_StartupFailureInCompatibilityMode(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusForbidden, map[string]any{
+		"code":    "INVALID_LICENSE",
+		"title":   "Invalid license",
+		"message": "invalid license",
+	}))
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, testOrgID)
+	client.SetPanicOnFailure(true)
+
+	var reasons []model.TerminationReason
+
+	client.SetTerminationReasonHandler(func(reason model.TerminationReason) {
+		reasons = append(reasons, reason)
+	})
+
+	// The custom handler replaces the default panic
+	assert.NotPanics(t, func() {
+		_ = client.Middleware()
+	})
+
+	require.Len(t, reasons, 1)
+
+	reason := reasons[0]
+	assert.Equal(t, cn.ErrNoValidLicenses.Error(), reason.Code)
+	assert.Equal(t, model.TerminationCauseStartupFailure, reason.Cause)
+	assert.Equal(t, []string{testOrgID}, reason.OrganizationIDs)
+	assert.Equal(t, http.StatusForbidden, reason.LastStatusCode)
+	require.NotNil(t, reason.LastResponse)
+	assert.Equal(t, "INVALID_LICENSE", reason.LastResponse.Code)
+}
+
+func TestTermination_BackgroundRefresh(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          any
+		expectedCause model.TerminationCause
+	}{
+		{
+			name:   "License revoked",
+			status: http.StatusForbidden,
+			body: map[string]any{
+				"code":    "LICENSE_REVOKED",
+				"message": "license revoked",
+			},
+			expectedCause: model.TerminationCauseLicenseRevoked,
+		},
+		{
+			name:          "Grace period exhausted",
+			status:        http.StatusOK,
+			body:          ValidationResult(false, 0),
+			expectedCause: model.TerminationCauseGracePeriodExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var revoked atomic.Bool
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if revoked.Load() {
+					JSONResponse(t, tt.status, tt.body)(w, r)
+					return
+				}
+
+				JSONResponse(t, http.StatusOK, ValidationResult(true, 90))(w, r)
+			}))
+			defer ts.Close()
+
+			var l log.Logger = testlogger.New()
+
+			client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l, validation.WithLicenseURL(ts.URL))
+			require.NoError(t, err)
+
+			client.SetHTTPClient(newTestClient(ts))
+
+			var reasons []model.TerminationReason
+
+			client.SetTerminationReasonHandler(func(reason model.TerminationReason) {
+				reasons = append(reasons, reason)
+			})
+
+			require.NoError(t, client.ValidateWithRetry(context.Background()))
+			assert.Empty(t, reasons)
+
+			revoked.Store(true)
+
+			err = client.ValidateWithRetry(context.Background())
+			require.ErrorIs(t, err, cn.ErrNoValidLicenses)
+			require.Len(t, reasons, 1)
+			assert.Equal(t, tt.expectedCause, reasons[0].Cause)
+			assert.Equal(t, []string{testOrgID}, reasons[0].OrganizationIDs)
+		})
+	}
+}
+
+func TestTermination_ServerErrorDoesNotTerminate(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusServiceUnavailable, nil))
+	defer ts.Close()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, testOrgID, &l, validation.WithLicenseURL(ts.URL))
+	require.NoError(t, err)
+
+	client.SetHTTPClient(newTestClient(ts))
+	client.SetTerminationReasonHandler(func(reason model.TerminationReason) {
+		t.Fatalf("unexpected termination: %s", reason)
+	})
+
+	require.NoError(t, client.ValidateWithRetry(context.Background()))
+}
+
+func TestTermination_StartupFailureWithCustomHandler(t *testing.T) {
+	ts := httptest.NewServer(JSONResponse(t, http.StatusForbidden, map[string]any{
+		"code":    "INVALID_LICENSE",
+		"message": "invalid license",
+	}))
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, testOrgID)
+
+	var reasons []model.TerminationReason
+
+	client.SetTerminationReasonHandler(func(reason model.TerminationReason) {
+		reasons = append(reasons, reason)
+	})
+
+	// Without the compatibility mode, the installed handler still sees the startup failure
+	require.ErrorIs(t, client.Start(context.Background()), cn.ErrNoValidLicenses)
+
+	assert.NotPanics(t, func() {
+		_ = client.Middleware()
+		_ = client.Middleware()
+	})
+
+	require.Len(t, reasons, 1, "the handler is invoked once")
+	assert.Equal(t, model.TerminationCauseStartupFailure, reasons[0].Cause)
+	assert.Equal(t, cn.ErrNoValidLicenses.Error(), reasons[0].Code)
+	assert.Equal(t, []string{testOrgID}, reasons[0].OrganizationIDs)
+}
+
+func TestTermination_BackgroundRefreshWithoutHandler(t *testing.T) {
+	var revoked atomic.Bool
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if revoked.Load() {
+			JSONResponse(t, http.StatusForbidden, map[string]any{
+				"code":    "LICENSE_REVOKED",
+				"message": "license revoked",
+			})(w, r)
+
+			return
+		}
+
+		JSONResponse(t, http.StatusOK, ValidationResult(true, 90))(w, r)
+	}))
+	defer ts.Close()
+
+	client := newStartupTestClient(t, ts, testOrgID)
+	require.NoError(t, client.Start(context.Background()))
+
+	revoked.Store(true)
+
+	// Without a handler or the compatibility mode, a revocation must not reach the default panic
+	assert.NotPanics(t, func() {
+		require.ErrorIs(t, client.Refresh(context.Background()), cn.ErrNoValidLicenses)
+	})
+
+	assert.Equal(t, model.StateRevoked, client.State(testOrgID))
+
+	handler := client.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(cn.OrganizationIDHeader, testOrgID)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code, "the guard rejects the revoked organization")
+}
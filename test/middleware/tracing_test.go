@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/lib-commons/commons/log"
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/middleware"
+	"github.com/LerianStudio/lib-license-go/test/helper/testlogger"
+	"github.com/LerianStudio/lib-license-go/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spansNamed returns the recorded spans with the given name
+func spansNamed(recorder *tracetest.SpanRecorder, name string) []sdktrace.ReadOnlySpan {
+	var spans []sdktrace.ReadOnlySpan
+
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			spans = append(spans, span)
+		}
+	}
+
+	return spans
+}
+
+// spanAttribute returns the value of a span attribute
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestTracing_RequestPath(t *testing.T) {
+	traceparents := make(chan string, 10)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("traceparent")
+		JSONResponse(t, http.StatusOK, ValidationResult(true, 90))(w, r)
+	}))
+	defer ts.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	var l log.Logger = testlogger.New()
+
+	client := middleware.NewLicenseClient(testAppID, testLicenseKey, "org-a", &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithHTTPClient(newTestClient(ts)),
+		validation.WithCacheTTL(time.Millisecond),
+		validation.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+	require.NotNil(t, client)
+	t.Cleanup(client.ShutdownBackgroundRefresh)
+	require.NoError(t, client.Start(context.Background()))
+
+	// Let the startup result expire so the request goes to the license gateway
+	time.Sleep(20 * time.Millisecond)
+
+	for len(traceparents) > 0 {
+		<-traceparents
+	}
+
+	app := fiber.New()
+	app.Get("/", client.Middleware(), func(c *fiber.Ctx) error {
+		return c.SendString("success")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(cn.OrganizationIDHeader, "org-a")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	checks := spansNamed(recorder, "license.check")
+	require.Len(t, checks, 1)
+
+	check := checks[0]
+	orgID, _ := spanAttribute(check, "license.organization.id")
+	assert.Equal(t, "org-a", orgID.AsString())
+	outcome, _ := spanAttribute(check, "license.outcome")
+	assert.Equal(t, "valid", outcome.AsString())
+
+	lookups := spansNamed(recorder, "license.cache.lookup")
+	require.NotEmpty(t, lookups)
+	hit, _ := spanAttribute(lookups[len(lookups)-1], "license.cache.hit")
+	assert.False(t, hit.AsBool())
+	assert.Equal(t, check.SpanContext().SpanID(), lookups[len(lookups)-1].Parent().SpanID())
+
+	// The gateway call is a client span with the HTTP conventions, propagated with traceparent
+	var gateway sdktrace.ReadOnlySpan
+
+	for _, span := range spansNamed(recorder, http.MethodPost) {
+		if span.SpanContext().TraceID() == check.SpanContext().TraceID() {
+			gateway = span
+		}
+	}
+
+	require.NotNil(t, gateway)
+	assert.Equal(t, trace.SpanKindClient, gateway.SpanKind())
+
+	status, _ := spanAttribute(gateway, "http.response.status_code")
+	assert.Equal(t, int64(http.StatusOK), status.AsInt64())
+	method, _ := spanAttribute(gateway, "http.request.method")
+	assert.Equal(t, http.MethodPost, method.AsString())
+	url, _ := spanAttribute(gateway, "url.full")
+	assert.Equal(t, ts.URL+"/licenses/validate", url.AsString())
+
+	traceparent := <-traceparents
+	assert.Contains(t, traceparent, gateway.SpanContext().TraceID().String())
+	assert.Contains(t, traceparent, gateway.SpanContext().SpanID().String())
+
+	// The license key is never recorded
+	for _, span := range recorder.Ended() {
+		for _, kv := range span.Attributes() {
+			assert.False(t, strings.Contains(kv.Value.Emit(), testLicenseKey), "span %s records the license key in %s", span.Name(), kv.Key)
+		}
+	}
+}
+
+func TestTracing_RefreshRetries(t *testing.T) {
+	// An unreachable license server is retried
+	ts := httptest.NewServer(JSONResponse(t, http.StatusOK, ValidationResult(true, 90)))
+	ts.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	var l log.Logger = testlogger.New()
+
+	client, err := validation.New(testAppID, testLicenseKey, "org-a", &l,
+		validation.WithLicenseURL(ts.URL),
+		validation.WithRetryPolicy(2, time.Millisecond),
+		validation.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+	require.NoError(t, err)
+
+	require.Error(t, client.ValidateWithRetry(context.Background()))
+
+	refreshes := spansNamed(recorder, "license.refresh")
+	require.Len(t, refreshes, 1)
+	assert.Equal(t, "Error", refreshes[0].Status().Code.String())
+
+	attempts := spansNamed(recorder, "license.refresh.attempt")
+	require.Len(t, attempts, 2)
+
+	for i, attempt := range attempts {
+		number, _ := spanAttribute(attempt, "license.attempt")
+		assert.Equal(t, int64(i+1), number.AsInt64())
+		assert.Equal(t, refreshes[0].SpanContext().SpanID(), attempt.Parent().SpanID())
+	}
+
+	validations := spansNamed(recorder, "license.validate")
+	require.Len(t, validations, 2)
+
+	outcome, _ := spanAttribute(validations[0], "license.outcome")
+	assert.Equal(t, "error", outcome.AsString())
+	assert.Equal(t, attempts[0].SpanContext().SpanID(), validations[0].Parent().SpanID())
+}
@@ -128,7 +128,7 @@ func RunTestCases(t *testing.T, testCases []TestCase) {
 			defer ts.Close()
 
 			// Set the required environment variables
-			t.Setenv("MIDAZ_LICENSE_URL", ts.URL)
+			t.Setenv(cn.EnvLicenseURL, ts.URL)
 			t.Setenv(cn.EnvOrganizationIDs, testOrgID)
 
 			// Create a mock logger
@@ -160,6 +160,8 @@ func RunTestCases(t *testing.T, testCases []TestCase) {
 			client := middleware.NewLicenseClient(testAppID, testOrgID, testLicenseKey, logger)
 
 			if tc.ExpectedPanic {
+				client.SetPanicOnFailure(true)
+
 				assert.Panics(t, func() {
 					_, _ = client.TestValidate(context.Background())
 				})
@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	libLicense "github.com/LerianStudio/lib-commons/commons/license"
@@ -15,28 +19,55 @@ import (
 	"github.com/LerianStudio/lib-license-go/internal/api"
 	"github.com/LerianStudio/lib-license-go/internal/cache"
 	"github.com/LerianStudio/lib-license-go/internal/config"
+	"github.com/LerianStudio/lib-license-go/internal/offline"
 	"github.com/LerianStudio/lib-license-go/internal/refresh"
+	"github.com/LerianStudio/lib-license-go/internal/snapshot"
+	"github.com/LerianStudio/lib-license-go/internal/telemetry"
 	"github.com/LerianStudio/lib-license-go/model"
 	"github.com/LerianStudio/lib-license-go/pkg"
 	pkgHTTP "github.com/LerianStudio/lib-license-go/pkg/net/http"
+	"github.com/LerianStudio/lib-license-go/provider"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client handles license validation with caching and background refresh
 type Client struct {
 	config          *config.ClientConfig
 	apiClient       *api.Client
+	provider        provider.LicenseProvider
+	discoverer      provider.Discoverer
 	cacheManager    *cache.Manager
+	snapshotStore   *snapshot.Store
 	refreshManager  *refresh.Manager
 	shutdownManager *libLicense.ManagerShutdown
+	metrics         *telemetry.Metrics
+	tracer          trace.Tracer
 	logger          log.Logger
+	// terminationMu guards lastTermination, the structured reason of the last requested termination,
+	// and customTermination, set once a termination handler is installed
+	terminationMu     sync.Mutex
+	lastTermination   *model.TerminationReason
+	customTermination bool
+	// orgMu guards the configured organization IDs, which can change at runtime, the organizations added
+	// with AddOrganization, which discovery keeps serving, and the event handler
+	orgMu           sync.RWMutex
+	addedOrgIDs     []string
+	orgEventHandler func(model.OrganizationEvent)
+	// stateMu guards the license status of each organization and the state change subscribers
+	stateMu       sync.Mutex
+	statuses      map[string]model.OrganizationStatus
+	stateHandlers []func(model.StateTransition)
+	// triggeredRefresh is set while a refresh started with TriggerRefresh runs
+	triggeredRefresh atomic.Bool
 	// IsGlobal indicates if this client is running in global-plugin mode
 	IsGlobal bool
 }
 
 // This method has been moved to the end of the file
 
-// New creates a new license validation client
-func New(appID, licenseKey, orgIDs string, logger *log.Logger) (*Client, error) {
+// New creates a new license validation client.
+// Options override the defaults for timeouts, refresh, retries, caching and fallback behavior.
+func New(appID, licenseKey, orgIDs string, logger *log.Logger, opts ...Option) (*Client, error) {
 	// Initialize logger
 	var l log.Logger
 	if logger != nil {
@@ -50,11 +81,56 @@ func New(appID, licenseKey, orgIDs string, logger *log.Logger) (*Client, error)
 
 	// Create and validate config
 	cfg := &config.ClientConfig{
-		AppName:         appID,
-		LicenseKey:      licenseKey,
-		OrganizationIDs: parsedOrgIDs,
-		HTTPTimeout:     cn.DefaultHTTPTimeoutSeconds * time.Second,
-		RefreshInterval: cn.DefaultRefreshIntervalDays * 24 * time.Hour,
+		AppName:           appID,
+		LicenseKey:        licenseKey,
+		OrganizationIDs:   parsedOrgIDs,
+		HTTPTimeout:       cn.DefaultHTTPTimeoutSeconds * time.Second,
+		RefreshInterval:   cn.DefaultRefreshIntervalDays * 24 * time.Hour,
+		MaxRetries:        cn.DefaultMaxRetries,
+		RetryBackoff:      cn.DefaultRetryBackoffSeconds * time.Second,
+		CacheTTL:          cn.CacheTTL,
+		FallbackGraceDays: cn.FallbackExpiryDaysLeft,
+		SnapshotMaxAge:    cn.DefaultSnapshotMaxAgeDays * 24 * time.Hour,
+		MaxConcurrency:    cn.DefaultMaxConcurrency,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Each organization gets the HTTP timeout unless configured otherwise
+	if cfg.OrganizationTimeout == 0 {
+		cfg.OrganizationTimeout = cfg.HTTPTimeout
+	}
+
+	if cfg.LicenseFile == "" && cfg.Provider == nil {
+		cfg.LicenseFile = os.Getenv(cn.EnvLicenseFile)
+	}
+
+	if cfg.SnapshotFile == "" {
+		cfg.SnapshotFile = os.Getenv(cn.EnvLicenseSnapshotFile)
+	}
+
+	if !cfg.DiscoverOrganizations {
+		cfg.DiscoverOrganizations = strings.EqualFold(os.Getenv(cn.EnvOrganizationDiscovery), "true")
+	}
+
+	// In discovery mode the configured organizations only filter the discovered ones
+	if cfg.DiscoverOrganizations {
+		if slices.ContainsFunc(cfg.OrganizationIDs, func(id string) bool { return strings.EqualFold(id, cn.GlobalPluginValue) }) {
+			err := errors.New("organization discovery cannot be used in global plugin mode")
+			l.Errorf("Invalid configuration: %s", err.Error())
+
+			return nil, err
+		}
+
+		cfg.OrganizationFilter = cfg.OrganizationIDs
+		cfg.OrganizationIDs = []string{}
+	}
+
+	if err := cfg.ResolveBaseURL(); err != nil {
+		l.Errorf("Invalid configuration: %s", err.Error())
+		return nil, err
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -63,19 +139,49 @@ func New(appID, licenseKey, orgIDs string, logger *log.Logger) (*Client, error)
 	}
 
 	// Create cache manager
-	cacheManager, err := cache.New(l)
+	cacheManager, err := cache.New(cfg.CacheTTL, l)
 	if err != nil {
 		l.Errorf("Failed to initialize cache: %s", err.Error())
 		return nil, err
 	}
 
-	// Create HTTP client
-	httpClient := &http.Client{
-		Timeout: cfg.HTTPTimeout,
+	// Create API client (builds a default HTTP client when none is configured)
+	apiClient := api.New(cfg, cfg.HTTPClient, l)
+
+	// A custom provider takes precedence over the offline license file, which takes precedence over the license server
+	var licenseProvider provider.LicenseProvider = apiClient
+
+	switch {
+	case cfg.Provider != nil:
+		l.Infof("Using custom license provider %T", cfg.Provider)
+
+		licenseProvider = cfg.Provider
+	case cfg.LicenseFile != "":
+		l.Infof("Using offline license file %s", cfg.LicenseFile)
+
+		licenseProvider = offline.New(cfg, l)
+	}
+
+	var discoverer provider.Discoverer
+	if cfg.DiscoverOrganizations {
+		var ok bool
+		if discoverer, ok = licenseProvider.(provider.Discoverer); !ok {
+			err := errors.New("license provider does not support organization discovery")
+			l.Errorf("Invalid configuration: %s", err.Error())
+
+			return nil, err
+		}
 	}
 
-	// Create API client
-	apiClient := api.New(cfg, httpClient, l)
+	// Load the persisted snapshot; a missing or tampered file only disables the offline window
+	var snapshotStore *snapshot.Store
+	if cfg.SnapshotFile != "" {
+		snapshotStore = snapshot.New(cfg.SnapshotFile, cfg.AppName, cfg.LicenseKey, l)
+
+		if err := snapshotStore.Load(); err != nil {
+			l.Warnf("Ignoring license snapshot %s - error: %s", cfg.SnapshotFile, err.Error())
+		}
+	}
 
 	// Create shutdown manager
 	shutdownManager := libLicense.New()
@@ -84,13 +190,20 @@ func New(appID, licenseKey, orgIDs string, logger *log.Logger) (*Client, error)
 	client := &Client{
 		config:          cfg,
 		apiClient:       apiClient,
+		provider:        licenseProvider,
+		discoverer:      discoverer,
 		cacheManager:    cacheManager,
+		snapshotStore:   snapshotStore,
 		shutdownManager: shutdownManager,
+		tracer:          telemetry.NewTracer(cfg.TracerProvider),
 		logger:          l,
+		statuses:        make(map[string]model.OrganizationStatus),
 	}
 
+	apiClient.SetTracer(client.tracer)
+
 	// detect global plugin mode
-	client.IsGlobal = len(parsedOrgIDs) == 1 && strings.EqualFold(parsedOrgIDs[0], cn.GlobalPluginValue)
+	client.IsGlobal = len(cfg.OrganizationIDs) == 1 && strings.EqualFold(cfg.OrganizationIDs[0], cn.GlobalPluginValue)
 	if client.IsGlobal {
 		l.Debugf("Validation client initialized in global plugin mode")
 	}
@@ -99,6 +212,18 @@ func New(appID, licenseKey, orgIDs string, logger *log.Logger) (*Client, error)
 	refreshManager := refresh.New(client, cfg.RefreshInterval, l)
 	client.refreshManager = refreshManager
 
+	if cfg.MeterProvider != nil {
+		metrics, err := telemetry.NewMetrics(cfg.MeterProvider, refreshManager.LastSuccessfulRefresh)
+		if err != nil {
+			l.Errorf("Failed to initialize metrics: %s", err.Error())
+			return nil, err
+		}
+
+		client.metrics = metrics
+		cacheManager.SetMetrics(metrics)
+		apiClient.SetMetrics(metrics)
+	}
+
 	return client, nil
 }
 
@@ -111,8 +236,16 @@ func (c *Client) TestValidate(ctx context.Context) (model.ValidationResult, erro
 
 // ValidateOrganizationWithCache validates a license for a specific organization ID with caching
 func (c *Client) ValidateOrganizationWithCache(ctx context.Context, orgID string) (model.ValidationResult, error) {
+	_, span := c.tracer.Start(ctx, "license.cache.lookup", trace.WithAttributes(telemetry.OrganizationIDKey.String(orgID)))
+
 	// Check if the organization ID is already in the cache
-	if result, found := c.cacheManager.Get(orgID); found {
+	result, found := c.cacheManager.Get(orgID)
+
+	span.SetAttributes(telemetry.CacheHitKey.Bool(found))
+	span.End()
+
+	if found {
+		c.markCached(orgID)
 		return result, nil
 	}
 
@@ -120,158 +253,288 @@ func (c *Client) ValidateOrganizationWithCache(ctx context.Context, orgID string
 	return c.validateSingleOrganization(ctx, orgID)
 }
 
-// ValidateAllOrganizations performs validation for all organization IDs
-// At least one organization must have a valid license for the application to continue.
-// This function collects all validation errors but will not terminate unless all validations fail.
-func (c *Client) ValidateAllOrganizations(ctx context.Context) (model.ValidationResult, error) {
+// ValidateStartup validates every configured organization and returns a per-organization report.
+// It never panics: when no organization has a valid license it returns a *StartupError wrapping
+// cn.ErrNoValidLicenses (or cn.ErrNoOrganizationIDs when none are configured).
+func (c *Client) ValidateStartup(ctx context.Context) (Report, error) {
+	report := Report{Global: c.IsGlobal}
+
+	// Keep serving the previously discovered organizations when discovery fails
+	var discoveryErr error
+	if c.discoverer != nil {
+		discoveryErr = c.discoverOrganizations(ctx)
+	}
+
+	orgIDs := c.GetOrganizationIDs()
+
 	// If no organization IDs are configured, return an error
-	if len(c.config.OrganizationIDs) == 0 {
-		return model.ValidationResult{}, cn.ErrNoOrganizationIDs
+	if len(orgIDs) == 0 {
+		startupErr := &StartupError{Code: cn.ErrNoOrganizationIDs}
+		if discoveryErr != nil {
+			startupErr.Errors = []error{discoveryErr}
+		}
+
+		return report, startupErr
 	}
 
 	// Special handling for global plugin mode
 	if c.IsGlobal {
-		return c.validateSingleOrganization(ctx, cn.GlobalPluginValue)
+		orgIDs = []string{cn.GlobalPluginValue}
+	}
+
+	report.Organizations = c.validateOrganizations(ctx, orgIDs)
+
+	if len(report.ValidOrganizationIDs()) > 0 {
+		return report, nil
+	}
+
+	startupErr := &StartupError{
+		Code:            cn.ErrNoValidLicenses,
+		OrganizationIDs: report.FailedOrganizationIDs(),
 	}
 
-	// Process all organization IDs at once
-	return c.validateMultipleOrganizations(ctx, c.config.OrganizationIDs)
+	for _, org := range report.Organizations {
+		startupErr.Errors = append(startupErr.Errors, fmt.Errorf("org %s: %w", org.OrganizationID, org.Err))
+	}
+
+	c.logger.Errorf("%s: All license validations failed", cn.ErrNoValidLicenses.Error())
+	c.logger.Debugf("Org IDs error: %s", startupErr.Error())
+
+	return report, startupErr
 }
 
-// validateMultipleOrganizations performs validation for multiple organization IDs
-func (c *Client) validateMultipleOrganizations(ctx context.Context, orgIDs []string) (model.ValidationResult, error) {
-	var validFound bool
+// ValidateAllOrganizations performs validation for all organization IDs
+// At least one organization must have a valid license for the application to continue.
+// It returns the last valid result in configuration order, or the error from ValidateStartup.
+// The error is routed to the shutdown manager when a termination handler is installed or the PanicOnFailure
+// compatibility mode is enabled.
+func (c *Client) ValidateAllOrganizations(ctx context.Context) (model.ValidationResult, error) {
+	report, err := c.ValidateStartup(ctx)
+	if err != nil {
+		if (c.config.PanicOnFailure || c.HasTerminationHandler()) && !errors.Is(err, cn.ErrNoOrganizationIDs) {
+			c.Terminate(NewTerminationReason(model.TerminationCauseStartupFailure, report, err))
+		}
+
+		return model.ValidationResult{}, err
+	}
 
 	var lastValidResult model.ValidationResult
 
-	var allOrgErrors []error
+	for _, org := range report.Organizations {
+		if org.Valid() {
+			lastValidResult = org.Result
+		}
+	}
 
-	// Process each organization ID to validate licenses
-	// We still need to iterate through orgIDs for proper error handling and caching
-	// but we're no longer creating temporary API clients for each org
-	for _, orgID := range orgIDs {
-		// Use the API client directly with this organization ID
-		result, err := c.apiClient.ValidateOrganization(ctx, orgID)
+	return lastValidResult, nil
+}
 
-		// Check if this is a server error (5xx) which should be treated as valid with grace period
-		if apiErr, ok := err.(*pkg.HTTPError); ok && apiErr.StatusCode >= 500 && apiErr.StatusCode < 600 {
-			c.logger.Debugf("License server error (5xx) detected for organization %s, treating as valid - error: %s",
-				orgID, apiErr.Error())
+// validateOrganizations validates the organizations in parallel, at most MaxConcurrency at a time,
+// and returns their reports in the order of orgIDs regardless of completion order
+func (c *Client) validateOrganizations(ctx context.Context, orgIDs []string) []OrganizationReport {
+	reports := make([]OrganizationReport, len(orgIDs))
+	batchResults := c.validateBatch(ctx, orgIDs)
 
-			// Create a temporary valid license result
-			tempResult := model.ValidationResult{
-				Valid:             true,
-				ExpiryDaysLeft:    cn.FallbackExpiryDaysLeft,
-				ActiveGracePeriod: true,
-			}
+	sem := make(chan struct{}, c.config.MaxConcurrency)
 
-			// Mark as valid
-			validFound = true
-			lastValidResult = tempResult
+	var wg sync.WaitGroup
 
+	for i, orgID := range orgIDs {
+		if result, ok := batchResults[orgID]; ok {
+			reports[i] = c.evaluateResult(orgID, result, nil)
 			continue
 		}
 
-		if err == nil && (result.Valid || result.ActiveGracePeriod) {
-			// If this organization has a valid license, we're good
-			validFound = true
-			lastValidResult = result
+		wg.Add(1)
 
-			c.logValidResult(orgID, result)
-			c.cacheManager.Store(orgID, result)
-		} else {
-			if err != nil {
-				allOrgErrors = append(allOrgErrors, fmt.Errorf("org %s: %w", orgID, err))
-
-				c.logger.Warnf("Validation failed for org %s", orgID)
-				c.logger.Debugf("error: %v", err)
-
-				// For APIErrors, we want to log appropriately but not terminate
-				if apiErr, ok := err.(*pkg.HTTPError); ok {
-					c.logger.Debugf("Organization %s license validation failed with status code %d: %v",
-						orgID, apiErr.StatusCode, apiErr.Error())
-				} else {
-					c.logger.Debugf("Organization %s has invalid license: %v", orgID, err)
-				}
-			}
-		}
+		go func(i int, orgID string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			orgCtx, cancel := context.WithTimeout(ctx, c.config.OrganizationTimeout)
+			defer cancel()
+
+			reports[i] = c.validateOrganization(orgCtx, orgID)
+		}(i, orgID)
 	}
 
-	// If no valid organizations, terminate the application
-	if !validFound {
-		var orgIDsErrorMsgs string
+	wg.Wait()
 
-		baseErrMsg := "All license validations failed"
+	c.flushSnapshot()
 
-		if len(allOrgErrors) > 0 {
-			errMsgs := make([]string, len(allOrgErrors))
-			for i, err := range allOrgErrors {
-				errMsgs[i] = err.Error()
-			}
+	return reports
+}
 
-			orgIDsErrorMsgs = fmt.Sprintf("[%s]", strings.Join(errMsgs, "; "))
-		} else {
-			orgIDsErrorMsgs = fmt.Sprintf("%s: No valid licenses found for any configured organization", baseErrMsg)
-		}
+// validateBatch validates the organizations in a single call when the provider supports it.
+// It returns nil when the provider has no batch method or the batch call fails.
+func (c *Client) validateBatch(ctx context.Context, orgIDs []string) map[string]model.ValidationResult {
+	batchProvider, ok := c.provider.(provider.BatchProvider)
+	if !ok || len(orgIDs) < 2 {
+		return nil
+	}
 
-		c.logger.Errorf("Exiting: %s: %s", cn.ErrNoValidLicenses.Error(), baseErrMsg)
-		c.logger.Debugf("Org IDs error: %s", orgIDsErrorMsgs)
-		panic(fmt.Sprintf("%s: %s", cn.ErrNoValidLicenses.Error(), orgIDsErrorMsgs))
+	results, err := batchProvider.ValidateBatch(ctx, orgIDs)
+	if err != nil {
+		c.logger.Debugf("Batch license validation failed, validating organizations one by one: %v", err)
+		return nil
 	}
 
-	return lastValidResult, nil
+	return results
 }
 
-// validateSingleOrganization performs validation for a specific organization ID
-// and handles the result (caching, logging, error handling)
-func (c *Client) validateSingleOrganization(ctx context.Context, orgID string) (model.ValidationResult, error) {
-	// Validate for this single org using the variadic performAPIValidation
-	result, err := c.apiClient.ValidateOrganization(ctx, orgID)
+// validateOrganization validates a single organization against the license provider
+func (c *Client) validateOrganization(ctx context.Context, orgID string) OrganizationReport {
+	ctx, span := c.tracer.Start(ctx, "license.validate", trace.WithAttributes(telemetry.OrganizationIDKey.String(orgID)))
+
+	result, err := c.provider.Validate(ctx, orgID)
+
+	report := c.evaluateResult(orgID, result, err)
+	telemetry.EndSpan(span, reportOutcome(report), report.Err)
+
+	return report
+}
+
+// reportOutcome names the outcome of an organization report for spans
+func reportOutcome(report OrganizationReport) string {
+	var forbiddenErr pkg.ForbiddenError
+
+	switch {
+	case report.Fallback:
+		return telemetry.OutcomeFallback
+	case errors.Is(report.Err, cn.ErrOrgLicenseInvalid):
+		return telemetry.OutcomeInvalid
+	case errors.As(report.Err, &forbiddenErr):
+		return telemetry.OutcomeRejected
+	case report.Err != nil:
+		return telemetry.OutcomeError
+	case report.Result.ActiveGracePeriod:
+		return telemetry.OutcomeGrace
+	default:
+		return telemetry.OutcomeValid
+	}
+}
+
+// evaluateResult turns a provider answer into an organization report.
+// Successful results are logged and cached; failures are recorded in the returned report.
+// Every report updates the license state of the organization.
+func (c *Client) evaluateResult(orgID string, result model.ValidationResult, err error) OrganizationReport {
+	report := OrganizationReport{OrganizationID: orgID}
+
 	if err != nil {
-		// Handle errors according to type
-		return c.handleAPIError(orgID, err)
+		report.Result, report.Fallback, report.Err = c.handleAPIError(orgID, err)
+		c.recordFailedValidation(orgID, err, report)
+
+		// A rejected license must not keep being served from the cache
+		var forbiddenErr pkg.ForbiddenError
+		if errors.As(report.Err, &forbiddenErr) {
+			c.cacheManager.Delete(orgID)
+		}
+
+		// A failed validation that still yields a result was answered by a fallback
+		source := model.SourceAPI
+		if report.Err == nil {
+			source = model.SourceFallback
+		}
+
+		c.updateState(orgID, report, source)
+
+		return report
 	}
 
-	errMsg := "No valid licenses found"
+	report.Result = result
 
 	// Check if the license is valid or in grace period
 	if !result.Valid && !result.ActiveGracePeriod {
-		// License is expired and not in grace period
-		c.logger.Errorf("Exiting: %s", errMsg)
-		panic(fmt.Sprintf("%s: %s", cn.ErrNoValidLicenses.Error(), errMsg))
+		c.logger.Warnf("Organization %s has no valid license", orgID)
+		c.metrics.RecordValidation(orgID, telemetry.OutcomeInvalid, 0, result)
+
+		report.Err = cn.ErrOrgLicenseInvalid
+		c.cacheManager.Delete(orgID)
+		c.updateState(orgID, report, model.SourceAPI)
+
+		return report
+	}
+
+	if result.ActiveGracePeriod {
+		c.metrics.RecordValidation(orgID, telemetry.OutcomeGrace, 0, result)
+	} else {
+		c.metrics.RecordValidation(orgID, telemetry.OutcomeValid, 0, result)
 	}
 
 	// Successful validation
 	c.logValidResult(orgID, result)
 	c.cacheManager.Store(orgID, result)
+	c.recordSnapshot(orgID, result)
+	c.updateState(orgID, report, model.SourceAPI)
 
-	return result, nil
+	return report
+}
+
+// validateSingleOrganization performs validation for a specific organization ID on the request path.
+// An expired license is returned as a result rather than an error so callers can reject the request.
+func (c *Client) validateSingleOrganization(ctx context.Context, orgID string) (model.ValidationResult, error) {
+	report := c.validateOrganization(ctx, orgID)
+	c.flushSnapshot()
+	if errors.Is(report.Err, cn.ErrOrgLicenseInvalid) {
+		return report.Result, nil
+	}
+
+	return report.Result, report.Err
 }
 
 // ValidateWithRetry implements refresh.Validator interface
-// It attempts to validate the license with retries
+// It attempts to validate the license with retries. When the license server explicitly rejects
+// every organization (revoked license or exhausted grace period) retrying cannot help, so the
+// termination is requested through the shutdown manager when a termination handler is installed
+// or the PanicOnFailure compatibility mode is enabled.
 func (c *Client) ValidateWithRetry(ctx context.Context) error {
+	ctx, span := c.tracer.Start(ctx, "license.refresh")
+
+	err := c.validateWithRetry(ctx)
+	telemetry.EndSpan(span, "", err)
+
+	return err
+}
+
+// validateWithRetry runs the validation attempts of ValidateWithRetry, each in its own span
+func (c *Client) validateWithRetry(ctx context.Context) error {
 	// Simple retry mechanism for background validation
-	maxRetries := 3
-	backoff := 5 * time.Second
+	maxRetries := c.config.MaxRetries
+	backoff := c.config.RetryBackoff
 
 	var lastErr error
 
 	for i := 0; i < maxRetries; i++ {
-		// Create a timeout context for this validation attempt
-		timeoutCtx, cancel := context.WithTimeout(ctx, c.config.HTTPTimeout)
+		attemptCtx, attemptSpan := c.tracer.Start(ctx, "license.refresh.attempt", trace.WithAttributes(telemetry.AttemptKey.Int(i+1)))
 
-		var err error
+		// Create a timeout context for this validation attempt
+		timeoutCtx, cancel := context.WithTimeout(attemptCtx, c.attemptTimeout())
 
-		_, err = c.ValidateAllOrganizations(timeoutCtx)
+		report, err := c.ValidateStartup(timeoutCtx)
 
 		// Always cancel the timeout context when done with this attempt
 		cancel()
+		telemetry.EndSpan(attemptSpan, "", err)
 
 		if err == nil {
 			return nil
 		}
 
+		if cause, fatal := refreshTerminationCause(report); fatal {
+			reason := NewTerminationReason(cause, report, err)
+
+			// Like at startup, terminate only when asked to; otherwise the guards reject the requests
+			if ctx.Value(noTerminationKey{}) == nil && (c.config.PanicOnFailure || c.HasTerminationHandler()) {
+				c.Terminate(reason)
+			} else {
+				c.logger.Errorf("License refresh failed: %s", reason)
+			}
+
+			return err
+		}
+
 		// Check if the error was due to context timeout or cancellation
 		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 			c.logger.Debugf("Validation attempt %d/%d timed out or was canceled", i+1, maxRetries)
@@ -305,58 +568,151 @@ func (c *Client) ValidateWithRetry(ctx context.Context) error {
 	return lastErr
 }
 
-// handleAPIError handles all API error cases
-// This is called for single organization validation (not from validateAndHandleAllOrgs)
-func (c *Client) handleAPIError(orgID string, err error) (model.ValidationResult, error) {
+// attemptTimeout bounds a validation attempt by the number of waves of parallel organization validations
+func (c *Client) attemptTimeout() time.Duration {
+	waves := (len(c.GetOrganizationIDs()) + c.config.MaxConcurrency - 1) / c.config.MaxConcurrency
+
+	return time.Duration(max(waves, 1)) * c.config.OrganizationTimeout
+}
+
+// handleAPIError handles all API error cases.
+// It returns the result to use for the organization, whether that result is a 5xx fallback,
+// and the error to report when the organization cannot be considered licensed.
+func (c *Client) handleAPIError(orgID string, err error) (model.ValidationResult, bool, error) {
 	// Handle APIErrors specially
-	if apiErr, ok := err.(*pkg.HTTPError); ok {
+	var apiErr *pkg.HTTPError
+	if errors.As(err, &apiErr) {
 		// Server errors (5xx) are treated as temporary and we fall back to cached value
 		if apiErr.StatusCode >= 500 && apiErr.StatusCode < 600 {
-			c.logger.Debugf("License server error (5xx) detected, treating as valid - error: %s", apiErr.Error())
+			c.logger.Debugf("License server error (5xx) detected for organization %s, treating as valid - error: %s",
+				orgID, apiErr.Error())
 
 			// Try to get any cached result for this org ID
 			if result, found := c.cacheManager.Get(orgID); found {
 				c.logger.Debugf("Using cached license validation for org %s due to server error", orgID)
-				return result, nil
+				c.metrics.RecordFallback(telemetry.FallbackCache)
+
+				return result, false, nil
+			}
+
+			if result, found := c.snapshotResult(orgID); found {
+				c.metrics.RecordFallback(telemetry.FallbackSnapshot)
+				return result, false, nil
 			}
 
 			// No cached result, return a temporary valid license
+			c.metrics.RecordFallback(telemetry.FallbackGrace)
+
 			return model.ValidationResult{
 				Valid:             true,
-				ExpiryDaysLeft:    cn.FallbackExpiryDaysLeft,
+				ExpiryDaysLeft:    c.config.FallbackGraceDays,
 				ActiveGracePeriod: true,
-			}, nil
+			}, true, nil
 		}
 
-		// Client errors (4xx) are fatal for single org validation - license is invalid
-		// In multi-org validation, these errors are handled in validateAndHandleAllOrgs
+		// Client errors (4xx) mean the license is invalid for this organization
 		if apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
-			// Check if we're in a multi-org validation process
-			if orgID != cn.GlobalPluginValue {
-				// For multi-org validation, just return the error so the loop can continue
-				return model.ValidationResult{}, pkg.ForbiddenError{
-					Code:    apiErr.Code,
-					Title:   apiErr.Title,
-					Message: apiErr.Message,
-				}
+			c.logger.Warnf("Validation failed for org %s", orgID)
+			c.logger.Debugf("Organization %s license validation failed with status code %d: %v",
+				orgID, apiErr.StatusCode, apiErr.Error())
+
+			return model.ValidationResult{}, false, pkg.ForbiddenError{
+				Code:    apiErr.Code,
+				Title:   apiErr.Title,
+				Message: apiErr.Message,
+				Err:     apiErr,
 			}
-
-			// For single org validation, terminate as before
-			c.logger.Errorf("Exiting: license validation failed with client error: %s", apiErr.Error())
-			panic(fmt.Sprintf("License validation failed with client error: %s", apiErr.Error()))
 		}
 	}
 
+	// Providers other than the license server reject organizations with a ForbiddenError
+	var forbiddenErr pkg.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		c.logger.Warnf("Validation failed for org %s", orgID)
+		c.logger.Debugf("Organization %s license was rejected: %v", orgID, forbiddenErr.Error())
+
+		return model.ValidationResult{}, false, forbiddenErr
+	}
+
 	// Handle connection errors by using the cached result if available
 	if pkgHTTP.IsConnectionError(err) {
 		if result, found := c.cacheManager.Get(orgID); found {
 			c.logger.Debugf("Using cached license validation for org %s due to connection error: %s", orgID, err.Error())
-			return result, nil
+			c.metrics.RecordFallback(telemetry.FallbackCache)
+
+			return result, false, nil
+		}
+
+		if result, found := c.snapshotResult(orgID); found {
+			c.metrics.RecordFallback(telemetry.FallbackSnapshot)
+			return result, false, nil
 		}
 	}
 
+	c.logger.Warnf("Validation failed for org %s", orgID)
+	c.logger.Debugf("Organization %s has invalid license: %v", orgID, err)
+
 	// For any other errors, just return the error
-	return model.ValidationResult{}, cn.ErrOrgLicenseValidationFail
+	return model.ValidationResult{}, false, cn.ErrOrgLicenseValidationFail
+}
+
+// recordFailedValidation counts a validation the license provider could not answer with a result
+func (c *Client) recordFailedValidation(orgID string, err error, report OrganizationReport) {
+	var statusCode int
+
+	var apiErr *pkg.HTTPError
+	if errors.As(err, &apiErr) {
+		statusCode = apiErr.StatusCode
+	}
+
+	var forbiddenErr pkg.ForbiddenError
+
+	switch {
+	case report.Err == nil:
+		c.metrics.RecordValidation(orgID, telemetry.OutcomeFallback, statusCode, report.Result)
+	case errors.As(report.Err, &forbiddenErr):
+		c.metrics.RecordValidation(orgID, telemetry.OutcomeRejected, statusCode, report.Result)
+	default:
+		c.metrics.RecordValidation(orgID, telemetry.OutcomeError, statusCode, report.Result)
+	}
+}
+
+// recordSnapshot records a successful validation result when the snapshot is enabled; flushSnapshot
+// persists it
+func (c *Client) recordSnapshot(orgID string, result model.ValidationResult) {
+	if c.snapshotStore == nil {
+		return
+	}
+
+	c.snapshotStore.Record(orgID, result, time.Now())
+}
+
+// flushSnapshot writes the results recorded since the last write, once per batch of validations
+func (c *Client) flushSnapshot() {
+	if c.snapshotStore == nil {
+		return
+	}
+
+	if err := c.snapshotStore.Flush(); err != nil {
+		c.logger.Warnf("Failed to persist license snapshot - error: %s", err.Error())
+	}
+}
+
+// snapshotResult returns the persisted result of an organization when it is within the offline window
+func (c *Client) snapshotResult(orgID string) (model.ValidationResult, bool) {
+	if c.snapshotStore == nil {
+		return model.ValidationResult{}, false
+	}
+
+	result, validatedAt, found := c.snapshotStore.Lookup(orgID, c.config.SnapshotMaxAge, time.Now())
+	if !found {
+		return model.ValidationResult{}, false
+	}
+
+	c.logger.Warnf("License server unavailable, using license snapshot of org %s validated at %s",
+		orgID, validatedAt.Format(time.RFC3339))
+
+	return result, true
 }
 
 // logValidResult handles a valid license response
@@ -423,6 +779,56 @@ func (c *Client) logGracePeriod(orgID string, expiryDays int) {
 	}
 }
 
+// CachedResult returns the cached validation result of an organization without contacting the license server
+func (c *Client) CachedResult(orgID string) (model.ValidationResult, bool) {
+	return c.cacheManager.Get(orgID)
+}
+
+// InvalidateCache evicts the cached result of a served organization (cn.GlobalPluginValue in global mode),
+// so its next request is validated against the license provider
+func (c *Client) InvalidateCache(orgID string) error {
+	served := c.HasOrganization(orgID)
+	if c.IsGlobal {
+		served = orgID == cn.GlobalPluginValue
+	}
+
+	if !served {
+		return fmt.Errorf("organization %s is not configured", orgID)
+	}
+
+	c.cacheManager.Delete(orgID)
+
+	return nil
+}
+
+// Refresh runs the background refresh immediately, with its retries and termination rules
+func (c *Client) Refresh(ctx context.Context) error {
+	return c.refreshManager.Refresh(ctx)
+}
+
+// noTerminationKey marks the context of a refresh that must not terminate the application
+type noTerminationKey struct{}
+
+// TriggerRefresh starts a refresh in the background and returns immediately. Unlike Refresh it never
+// terminates the application: a revoked license is only reflected in the state of its organizations.
+// It returns false when a triggered refresh is still running.
+func (c *Client) TriggerRefresh() bool {
+	if !c.triggeredRefresh.CompareAndSwap(false, true) {
+		return false
+	}
+
+	go func() {
+		defer c.triggeredRefresh.Store(false)
+
+		ctx := context.WithValue(context.Background(), noTerminationKey{}, true)
+		if err := c.refreshManager.Refresh(ctx); err != nil {
+			c.logger.Warnf("Triggered license refresh failed: %v", err)
+		}
+	}()
+
+	return true
+}
+
 // StartBackgroundRefresh runs a ticker to refresh license periodically
 func (c *Client) StartBackgroundRefresh(ctx context.Context) {
 	c.refreshManager.Start(ctx)
@@ -433,18 +839,46 @@ func (c *Client) ShutdownBackgroundRefresh() {
 	c.refreshManager.Shutdown()
 }
 
+// Tracer returns the tracer of the client, a no-op tracer when tracing is disabled
+func (c *Client) Tracer() trace.Tracer {
+	return c.tracer
+}
+
 // GetLogger returns the logger used by the client
 func (c *Client) GetLogger() log.Logger {
 	return c.logger
 }
 
-// GetOrganizationIDs returns the organization IDs configured for this client
+// GetBaseURL returns the license server endpoint used by this client
+func (c *Client) GetBaseURL() string {
+	return c.config.BaseURL
+}
+
+// GetOrganizationIDs returns a copy of the organization IDs currently served by this client
 func (c *Client) GetOrganizationIDs() []string {
 	if c == nil || c.config == nil {
 		return []string{}
 	}
 
-	return c.config.OrganizationIDs
+	c.orgMu.RLock()
+	defer c.orgMu.RUnlock()
+
+	return slices.Clone(c.config.OrganizationIDs)
+}
+
+// RetryBackoff returns the initial backoff between validation retries
+func (c *Client) RetryBackoff() time.Duration {
+	return c.config.RetryBackoff
+}
+
+// PanicOnFailure reports whether the legacy panic-on-failure compatibility mode is enabled
+func (c *Client) PanicOnFailure() bool {
+	return c.config.PanicOnFailure
+}
+
+// SetPanicOnFailure enables or disables the legacy panic-on-failure compatibility mode
+func (c *Client) SetPanicOnFailure(enabled bool) {
+	c.config.PanicOnFailure = enabled
 }
 
 // SetHTTPClient allows overriding the HTTP client (useful for testing)
@@ -459,5 +893,10 @@ func (c *Client) GetShutdownManager() *libLicense.ManagerShutdown {
 
 // SetTerminationHandler allows customizing how the application terminates when license validation fails
 func (c *Client) SetTerminationHandler(handler libLicense.Handler) {
+	if handler == nil {
+		return
+	}
+
 	c.shutdownManager.SetHandler(handler)
+	c.setCustomTermination()
 }
@@ -0,0 +1,168 @@
+package validation
+
+import (
+	"crypto/ed25519"
+	"net/http"
+	"time"
+
+	"github.com/LerianStudio/lib-license-go/internal/config"
+	"github.com/LerianStudio/lib-license-go/provider"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option customizes the configuration of a validation Client.
+// Options are applied over the defaults and the result is checked by config.ClientConfig.Validate.
+type Option func(*config.ClientConfig)
+
+// WithHTTPTimeout sets the timeout of each request to the license server
+func WithHTTPTimeout(timeout time.Duration) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.HTTPTimeout = timeout
+	}
+}
+
+// WithRefreshInterval sets how often licenses are revalidated in the background
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.RefreshInterval = interval
+	}
+}
+
+// WithRetryPolicy sets the number of background validation attempts and the initial backoff between them.
+// The backoff doubles after each failed attempt.
+func WithRetryPolicy(maxRetries int, backoff time.Duration) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.MaxRetries = maxRetries
+		cfg.RetryBackoff = backoff
+	}
+}
+
+// WithCacheTTL sets how long a successful validation result is served from cache
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.CacheTTL = ttl
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to reach the license server.
+// The client timeout is left untouched; WithHTTPTimeout still bounds background validation attempts.
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.HTTPClient = client
+	}
+}
+
+// WithFallbackGraceDays sets the expiry days reported for organizations assumed valid
+// when the license server answers with a 5xx error and no cached result exists
+func WithFallbackGraceDays(days int) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.FallbackGraceDays = days
+	}
+}
+
+// WithPanicOnFailure enables the legacy compatibility mode that terminates through the shutdown
+// manager (panicking by default) when startup validation fails
+func WithPanicOnFailure() Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.PanicOnFailure = true
+	}
+}
+
+// WithLicenseURL sets the license server endpoint, e.g. an on-prem license gateway.
+// It takes precedence over WithLicenseProfile and the LICENSE_URL and LICENSE_PROFILE environment variables.
+func WithLicenseURL(baseURL string) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.BaseURL = baseURL
+	}
+}
+
+// WithLicenseProfile selects a named license server profile (prod, dev, staging or custom).
+// It takes precedence over the LICENSE_URL and LICENSE_PROFILE environment variables.
+func WithLicenseProfile(profile string) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.Profile = profile
+	}
+}
+
+// WithLicenseFile validates organizations against a signed offline license file instead of the
+// license server, for air-gapped deployments. It takes precedence over the LICENSE_FILE environment variable.
+func WithLicenseFile(path string) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.LicenseFile = path
+	}
+}
+
+// WithLicensePublicKey trusts an Ed25519 public key, identified by the JWS key ID, to verify the
+// offline license file. Keys should be compiled into the application, never read from the deployment.
+func WithLicensePublicKey(keyID string, key ed25519.PublicKey) Option {
+	return func(cfg *config.ClientConfig) {
+		if cfg.PublicKeys == nil {
+			cfg.PublicKeys = make(map[string]ed25519.PublicKey)
+		}
+
+		cfg.PublicKeys[keyID] = key
+	}
+}
+
+// WithOrganizationDiscovery asks the license provider which organizations the license key entitles and
+// serves those instead of a fixed list, refreshing them with every background validation.
+// The organization IDs passed to New, if any, only filter the discovered organizations.
+// It can also be enabled with ORGANIZATION_DISCOVERY=true.
+func WithOrganizationDiscovery() Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.DiscoverOrganizations = true
+	}
+}
+
+// WithConcurrency sets how many organizations are validated in parallel at startup and on each refresh
+func WithConcurrency(limit int) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.MaxConcurrency = limit
+	}
+}
+
+// WithOrganizationTimeout bounds the validation of each organization, including a slow license server response
+func WithOrganizationTimeout(timeout time.Duration) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.OrganizationTimeout = timeout
+	}
+}
+
+// WithSnapshot persists the last successful validation result of each organization to path, so a restart
+// during a license server outage can rely on it for at most maxAge after the original validation.
+// The file is written atomically and protected by an HMAC keyed with the license key.
+// It takes precedence over the LICENSE_SNAPSHOT_FILE environment variable.
+func WithSnapshot(path string, maxAge time.Duration) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.SnapshotFile = path
+		cfg.SnapshotMaxAge = maxAge
+	}
+}
+
+// WithLicenseProvider validates organizations against a custom license provider, such as
+// provider.NewStatic in tests or a provider.NewChain failing over between sources.
+// It takes precedence over the offline license file and the license server.
+func WithLicenseProvider(p provider.LicenseProvider) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.Provider = p
+	}
+}
+
+// WithMeterProvider records the license client metrics on an OpenTelemetry meter provider, e.g. one
+// backed by the Prometheus exporter: validations by outcome, license gateway latency, cache lookups,
+// fallback activations, per-organization expiry and grace period gauges and the last successful refresh
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.MeterProvider = mp
+	}
+}
+
+// WithTracerProvider records spans for license checks, cache lookups, license gateway calls (propagating
+// the W3C trace context to the gateway), background refreshes and their retries. Spans carry the
+// organization ID and the outcome; the license key is never recorded.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(cfg *config.ClientConfig) {
+		cfg.TracerProvider = tp
+	}
+}
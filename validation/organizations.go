@@ -0,0 +1,235 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/lib-license-go/model"
+)
+
+// errGlobalOrganizations is returned when organizations are managed on a global plugin client
+var errGlobalOrganizations = errors.New("organizations cannot be managed in global plugin mode")
+
+// AddOrganization validates the organization immediately and, when its license is valid, starts serving it
+// without a restart. Adding an organization that is already configured is a no-op.
+func (c *Client) AddOrganization(ctx context.Context, orgID string) error {
+	orgID = strings.TrimSpace(orgID)
+	if orgID == "" {
+		return errors.New("organization ID is required")
+	}
+
+	if c.IsGlobal {
+		return errGlobalOrganizations
+	}
+
+	if c.HasOrganization(orgID) {
+		return nil
+	}
+
+	report := c.validateOrganization(ctx, orgID)
+	c.flushSnapshot()
+
+	if !report.Valid() {
+		c.logger.Warnf("Organization %s was not added: %v", orgID, report.Err)
+		c.forgetState(orgID)
+		c.emitOrganizationEvent(model.OrganizationEvent{
+			Type:           model.OrganizationRejected,
+			OrganizationID: orgID,
+			Result:         report.Result,
+			Err:            report.Err,
+		})
+
+		return fmt.Errorf("organization %s was not added: %w", orgID, report.Err)
+	}
+
+	c.orgMu.Lock()
+	added := !slices.Contains(c.config.OrganizationIDs, orgID)
+	if added {
+		c.config.OrganizationIDs = append(slices.Clip(c.config.OrganizationIDs), orgID)
+	}
+
+	if !slices.Contains(c.addedOrgIDs, orgID) {
+		c.addedOrgIDs = append(c.addedOrgIDs, orgID)
+	}
+	c.orgMu.Unlock()
+
+	if added {
+		c.logger.Infof("Organization %s added", orgID)
+		c.emitOrganizationEvent(model.OrganizationEvent{
+			Type:           model.OrganizationAdded,
+			OrganizationID: orgID,
+			Result:         report.Result,
+		})
+	}
+
+	return nil
+}
+
+// RemoveOrganization stops serving the organization and evicts its cached results.
+// The last organization cannot be removed.
+func (c *Client) RemoveOrganization(orgID string) error {
+	if c.IsGlobal {
+		return errGlobalOrganizations
+	}
+
+	c.orgMu.Lock()
+
+	i := slices.Index(c.config.OrganizationIDs, orgID)
+	if i < 0 {
+		c.orgMu.Unlock()
+		return fmt.Errorf("organization %s is not configured", orgID)
+	}
+
+	if len(c.config.OrganizationIDs) == 1 {
+		c.orgMu.Unlock()
+		return errors.New("at least one organization ID is required")
+	}
+
+	c.config.OrganizationIDs = slices.Delete(slices.Clone(c.config.OrganizationIDs), i, i+1)
+	c.addedOrgIDs = slices.DeleteFunc(c.addedOrgIDs, func(id string) bool { return id == orgID })
+	c.orgMu.Unlock()
+
+	c.evictOrganization(orgID)
+
+	return nil
+}
+
+// discoverOrganizations replaces the served organizations with the ones the license key entitles,
+// restricted to the configured filter, plus the ones added with AddOrganization. When discovery fails
+// the served organizations are kept; if there are none yet, see fallbackOrganizations.
+func (c *Client) discoverOrganizations(ctx context.Context) error {
+	discovered, err := c.discoverer.Organizations(ctx)
+	if err != nil {
+		c.logger.Warnf("Organization discovery failed - error: %s", err.Error())
+
+		if len(c.GetOrganizationIDs()) == 0 {
+			c.setOrganizations(c.fallbackOrganizations())
+		}
+
+		return fmt.Errorf("organization discovery failed: %w", err)
+	}
+
+	orgIDs := make([]string, 0, len(discovered))
+
+	for _, orgID := range discovered {
+		if orgID == "" || slices.Contains(orgIDs, orgID) {
+			continue
+		}
+
+		if len(c.config.OrganizationFilter) > 0 && !slices.Contains(c.config.OrganizationFilter, orgID) {
+			continue
+		}
+
+		orgIDs = append(orgIDs, orgID)
+	}
+
+	c.logger.Debugf("Discovered %d licensed organizations (%d before filtering)", len(orgIDs), len(discovered))
+
+	c.setOrganizations(orgIDs)
+
+	return nil
+}
+
+// fallbackOrganizations returns the organizations to serve when discovery fails before any succeeded:
+// the organizations of the persisted snapshot within the offline window, restricted to the configured
+// filter, or else the configured filter itself
+func (c *Client) fallbackOrganizations() []string {
+	if c.snapshotStore != nil {
+		orgIDs := slices.DeleteFunc(c.snapshotStore.OrganizationIDs(c.config.SnapshotMaxAge, time.Now()), func(orgID string) bool {
+			return len(c.config.OrganizationFilter) > 0 && !slices.Contains(c.config.OrganizationFilter, orgID)
+		})
+
+		if len(orgIDs) > 0 {
+			c.logger.Warnf("Serving the %d organizations of the license snapshot until discovery succeeds", len(orgIDs))
+			return orgIDs
+		}
+	}
+
+	if len(c.config.OrganizationFilter) > 0 {
+		c.logger.Warnf("Serving the %d configured organizations until discovery succeeds", len(c.config.OrganizationFilter))
+	}
+
+	return slices.Clone(c.config.OrganizationFilter)
+}
+
+// setOrganizations replaces the served organizations, keeping the ones added with AddOrganization,
+// evicting the removed ones and emitting events
+func (c *Client) setOrganizations(orgIDs []string) {
+	c.orgMu.Lock()
+	// Merge under the same lock so an organization added concurrently is not dropped
+	for _, orgID := range c.addedOrgIDs {
+		if !slices.Contains(orgIDs, orgID) {
+			orgIDs = append(orgIDs, orgID)
+		}
+	}
+
+	previous := c.config.OrganizationIDs
+	c.config.OrganizationIDs = orgIDs
+	c.orgMu.Unlock()
+
+	for _, orgID := range orgIDs {
+		if !slices.Contains(previous, orgID) {
+			c.logger.Infof("Organization %s added", orgID)
+			c.emitOrganizationEvent(model.OrganizationEvent{
+				Type:           model.OrganizationAdded,
+				OrganizationID: orgID,
+			})
+		}
+	}
+
+	for _, orgID := range previous {
+		if !slices.Contains(orgIDs, orgID) {
+			c.evictOrganization(orgID)
+		}
+	}
+}
+
+// evictOrganization drops the cached and persisted results of a removed organization and emits the event
+func (c *Client) evictOrganization(orgID string) {
+	c.cacheManager.Delete(orgID)
+	c.metrics.Forget(orgID)
+	c.forgetState(orgID)
+
+	if c.snapshotStore != nil {
+		if err := c.snapshotStore.Remove(orgID); err != nil {
+			c.logger.Warnf("Failed to remove org %s from the license snapshot - error: %s", orgID, err.Error())
+		}
+	}
+
+	c.logger.Infof("Organization %s removed", orgID)
+	c.emitOrganizationEvent(model.OrganizationEvent{
+		Type:           model.OrganizationRemoved,
+		OrganizationID: orgID,
+	})
+}
+
+// HasOrganization reports whether the organization is currently served by the client
+func (c *Client) HasOrganization(orgID string) bool {
+	c.orgMu.RLock()
+	defer c.orgMu.RUnlock()
+
+	return slices.Contains(c.config.OrganizationIDs, orgID)
+}
+
+// SetOrganizationEventHandler registers a handler called synchronously after every runtime organization change
+func (c *Client) SetOrganizationEventHandler(handler func(model.OrganizationEvent)) {
+	c.orgMu.Lock()
+	defer c.orgMu.Unlock()
+
+	c.orgEventHandler = handler
+}
+
+// emitOrganizationEvent calls the registered organization event handler, if any
+func (c *Client) emitOrganizationEvent(event model.OrganizationEvent) {
+	c.orgMu.RLock()
+	handler := c.orgEventHandler
+	c.orgMu.RUnlock()
+
+	if handler != nil {
+		handler(event)
+	}
+}
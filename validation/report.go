@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LerianStudio/lib-license-go/model"
+)
+
+// OrganizationReport holds the startup validation outcome for a single organization
+type OrganizationReport struct {
+	OrganizationID string
+	Result         model.ValidationResult
+	// Fallback indicates the result was not returned by the license server but
+	// assumed valid because the server answered with a 5xx error
+	Fallback bool
+	Err      error
+}
+
+// Valid reports whether the organization can be served (valid license or active grace period)
+func (r OrganizationReport) Valid() bool {
+	return r.Err == nil && (r.Result.Valid || r.Result.ActiveGracePeriod)
+}
+
+// Report summarizes a startup validation across all configured organizations
+type Report struct {
+	// Global indicates the report was produced in global plugin mode
+	Global        bool
+	Organizations []OrganizationReport
+}
+
+// ValidOrganizationIDs returns the organization IDs that passed validation, in configuration order
+func (r Report) ValidOrganizationIDs() []string {
+	ids := make([]string, 0, len(r.Organizations))
+
+	for _, org := range r.Organizations {
+		if org.Valid() {
+			ids = append(ids, org.OrganizationID)
+		}
+	}
+
+	return ids
+}
+
+// FailedOrganizationIDs returns the organization IDs that failed validation, in configuration order
+func (r Report) FailedOrganizationIDs() []string {
+	ids := make([]string, 0, len(r.Organizations))
+
+	for _, org := range r.Organizations {
+		if !org.Valid() {
+			ids = append(ids, org.OrganizationID)
+		}
+	}
+
+	return ids
+}
+
+// StartupError is returned when startup validation decides the application must not run.
+// It wraps the LCS error code (e.g. cn.ErrNoValidLicenses) and every per-organization failure,
+// so callers can use errors.Is against both.
+type StartupError struct {
+	Code            error
+	OrganizationIDs []string
+	Errors          []error
+}
+
+// Error implements the error interface.
+func (e *StartupError) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Code.Error()
+	}
+
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%s: [%s]", e.Code.Error(), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the LCS error code followed by the per-organization errors.
+func (e *StartupError) Unwrap() []error {
+	return append([]error{e.Code}, e.Errors...)
+}
@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"errors"
+	"time"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+)
+
+// OnStateChange subscribes handler to the license state transitions of every organization. Handlers are
+// called synchronously, in subscription order, by the goroutine that validated the organization, and
+// only when the state changes; the first validation of an organization is a transition from StateUnknown.
+func (c *Client) OnStateChange(handler func(model.StateTransition)) {
+	if handler == nil {
+		return
+	}
+
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	c.stateHandlers = append(c.stateHandlers, handler)
+}
+
+// State returns the license state of an organization, StateUnknown until it is validated
+func (c *Client) State(orgID string) model.LicenseState {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	if status, found := c.statuses[orgID]; found {
+		return status.State
+	}
+
+	return model.StateUnknown
+}
+
+// updateState records the status of an organization after a validation and notifies the subscribers
+// when its state changed
+func (c *Client) updateState(orgID string, report OrganizationReport, source model.ResultSource) {
+	to := reportState(report)
+
+	status := model.OrganizationStatus{
+		OrganizationID: orgID,
+		State:          to,
+		Result:         report.Result,
+		Source:         source,
+		ValidatedAt:    time.Now(),
+	}
+
+	if report.Err != nil {
+		status.Error = report.Err.Error()
+	}
+
+	c.stateMu.Lock()
+
+	from := model.StateUnknown
+	if previous, found := c.statuses[orgID]; found {
+		from = previous.State
+	}
+
+	c.statuses[orgID] = status
+	handlers := c.stateHandlers
+
+	c.stateMu.Unlock()
+
+	if from == to {
+		return
+	}
+
+	switch to {
+	case model.StateExpired, model.StateRevoked, model.StateOffline, model.StateGrace:
+		c.logger.Warnf("License state of org %s is now %s", orgID, to)
+	default:
+		c.logger.Infof("License state of org %s is now %s", orgID, to)
+	}
+
+	transition := model.StateTransition{
+		OrganizationID: orgID,
+		From:           from,
+		To:             to,
+		Result:         report.Result,
+		Err:            report.Err,
+		At:             status.ValidatedAt,
+	}
+
+	for _, handler := range handlers {
+		handler(transition)
+	}
+}
+
+// markCached records that the last result of an organization was served from the cache
+func (c *Client) markCached(orgID string) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	if status, found := c.statuses[orgID]; found && status.Source != model.SourceCache {
+		status.Source = model.SourceCache
+		c.statuses[orgID] = status
+	}
+}
+
+// forgetState drops the status of a removed organization
+func (c *Client) forgetState(orgID string) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	delete(c.statuses, orgID)
+}
+
+// reportState computes the license state of an organization from its validation report
+func reportState(report OrganizationReport) model.LicenseState {
+	var forbiddenErr pkg.ForbiddenError
+
+	switch {
+	case report.Fallback:
+		// Assumed valid while the license server answers 5xx
+		return model.StateOffline
+	case report.Err == nil, errors.Is(report.Err, cn.ErrOrgLicenseInvalid):
+		return report.Result.State()
+	case errors.As(report.Err, &forbiddenErr):
+		return model.StateRevoked
+	default:
+		return model.StateOffline
+	}
+}
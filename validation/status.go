@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"time"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/model"
+)
+
+// Status returns a snapshot of what the client currently believes about the license of each served
+// organization and about the background refresh. The snapshot is a copy; later validations do not change it.
+func (c *Client) Status() model.ClientStatus {
+	status := model.ClientStatus{
+		Mode:                  model.ModeMultiOrganization,
+		LastRefreshAttempt:    c.refreshManager.LastAttemptedRefresh(),
+		LastSuccessfulRefresh: c.refreshManager.LastSuccessfulRefresh(),
+		NextRefresh:           c.refreshManager.NextRefresh(),
+		GeneratedAt:           time.Now(),
+	}
+
+	// A custom provider or an offline license file does not use the license server
+	if c.provider == c.apiClient {
+		status.Endpoint = c.GetBaseURL()
+	}
+
+	orgIDs := c.GetOrganizationIDs()
+
+	// Global plugin mode validates a single application-wide license
+	if c.IsGlobal {
+		status.Mode = model.ModeGlobal
+		orgIDs = []string{cn.GlobalPluginValue}
+	}
+
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	status.Organizations = make([]model.OrganizationStatus, 0, len(orgIDs))
+
+	for _, orgID := range orgIDs {
+		org, found := c.statuses[orgID]
+		if !found {
+			org = model.OrganizationStatus{OrganizationID: orgID, State: model.StateUnknown}
+		}
+
+		status.Organizations = append(status.Organizations, org)
+	}
+
+	return status
+}
@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"errors"
+
+	cn "github.com/LerianStudio/lib-license-go/constant"
+	"github.com/LerianStudio/lib-license-go/model"
+	"github.com/LerianStudio/lib-license-go/pkg"
+)
+
+// NewTerminationReason builds a structured termination reason from a failed validation report
+func NewTerminationReason(cause model.TerminationCause, report Report, err error) model.TerminationReason {
+	reason := model.TerminationReason{
+		Code:            cn.ErrNoValidLicenses.Error(),
+		Cause:           cause,
+		OrganizationIDs: report.FailedOrganizationIDs(),
+	}
+
+	if err != nil {
+		reason.Message = err.Error()
+	}
+
+	var startupErr *StartupError
+	if errors.As(err, &startupErr) {
+		reason.Code = startupErr.Code.Error()
+	}
+
+	for _, org := range report.Organizations {
+		var httpErr *pkg.HTTPError
+		if errors.As(org.Err, &httpErr) {
+			reason.LastStatusCode = httpErr.StatusCode
+			reason.LastResponse = &model.ErrorResponse{
+				Code:    httpErr.Code,
+				Title:   httpErr.Title,
+				Message: httpErr.Message,
+			}
+		}
+	}
+
+	return reason
+}
+
+// isDefinitiveFailure reports whether the license server explicitly rejected the organization,
+// as opposed to a transient failure such as a connection error
+func isDefinitiveFailure(org OrganizationReport) bool {
+	if errors.Is(org.Err, cn.ErrOrgLicenseInvalid) {
+		return true
+	}
+
+	var forbiddenErr pkg.ForbiddenError
+
+	return errors.As(org.Err, &forbiddenErr)
+}
+
+// refreshTerminationCause returns the termination cause for a failed background refresh, and false
+// when at least one organization failed transiently and the application should keep running
+func refreshTerminationCause(report Report) (model.TerminationCause, bool) {
+	if len(report.Organizations) == 0 {
+		return "", false
+	}
+
+	cause := model.TerminationCauseGracePeriodExhausted
+
+	for _, org := range report.Organizations {
+		if org.Valid() || !isDefinitiveFailure(org) {
+			return "", false
+		}
+
+		if !errors.Is(org.Err, cn.ErrOrgLicenseInvalid) {
+			cause = model.TerminationCauseLicenseRevoked
+		}
+	}
+
+	return cause, true
+}
+
+// Terminate records the structured reason and invokes the shutdown manager termination handler
+func (c *Client) Terminate(reason model.TerminationReason) {
+	c.logger.Errorf("Exiting: %s", reason.String())
+
+	c.terminationMu.Lock()
+	c.lastTermination = &reason
+	c.terminationMu.Unlock()
+
+	c.shutdownManager.Terminate(reason.String())
+}
+
+// SetTerminationReasonHandler installs a termination handler on the shutdown manager that receives
+// the structured reason instead of its string form
+func (c *Client) SetTerminationReasonHandler(handler func(model.TerminationReason)) {
+	if handler == nil {
+		return
+	}
+
+	c.shutdownManager.SetHandler(func(reason string) {
+		handler(c.terminationReasonFor(reason))
+	})
+	c.setCustomTermination()
+}
+
+// HasTerminationHandler reports whether a custom termination handler replaced the default panic
+func (c *Client) HasTerminationHandler() bool {
+	c.terminationMu.Lock()
+	defer c.terminationMu.Unlock()
+
+	return c.customTermination
+}
+
+// setCustomTermination records that a custom termination handler was installed
+func (c *Client) setCustomTermination() {
+	c.terminationMu.Lock()
+	defer c.terminationMu.Unlock()
+
+	c.customTermination = true
+}
+
+// terminationReasonFor returns the structured reason recorded for the given string reason.
+// Terminations requested directly on the shutdown manager only carry the message.
+func (c *Client) terminationReasonFor(reason string) model.TerminationReason {
+	c.terminationMu.Lock()
+	defer c.terminationMu.Unlock()
+
+	if c.lastTermination != nil && c.lastTermination.String() == reason {
+		return *c.lastTermination
+	}
+
+	return model.TerminationReason{Message: reason}
+}